@@ -3,8 +3,14 @@ package config
 
 import (
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,81 +18,704 @@ type AppConfig struct {
 	ServerPort               string
 	ModioAPIKey              string
 	ModioGameID              string
+	ModioGameSlug            string // Path segment of the public routes, /api/v1/<slug>/...
 	ModioAPIDomain           string
+	ModioAPIVersion          string
+	VerifyGameOnStartup      bool // Fetch the game at startup and exit if MODIO_GAME_ID doesn't exist
 	CacheRefreshInterval     time.Duration
 	LightweightCheckInterval time.Duration
+	CatchUpThreshold         time.Duration // Downtime after which the startup sync preserves the event cursor
+	EventDedupWindow         time.Duration // How long processed event ids are remembered to skip replays
+	// EventDetailFetchConcurrency is how many mod detail requests an event cycle has in flight at once.
+	EventDetailFetchConcurrency int
+	// EventCursorRepairLookback is how far before now an impossible (future) event cursor is reset to.
+	EventCursorRepairLookback time.Duration
+	// EventCursorStaleThreshold is how old the event cursor may get, while no sync cycle succeeds either,
+	// before an event cycle runs a reconciliation full sync instead.
+	EventCursorStaleThreshold time.Duration
+	// AuthFailureCooldown is how long scheduled syncs pause after mod.io rejects the API key.
+	AuthFailureCooldown time.Duration
+	// InitialSyncRetries is how many times a failed startup full sync is retried, with doubling delays from
+	// one minute up to 30, before waiting for the scheduled full sync.
+	InitialSyncRetries int
+
+	// ModioRecordDir, when set, writes every mod.io request/response pair there as a golden file.
+	// ModioReplayDir serves mod.io responses from such recordings instead of the network (no API key
+	// needed); it takes precedence over recording.
+	ModioRecordDir string
+	ModioReplayDir string
+
+	// ModioRetryAttempts is how many times a mod.io GET is tried when it fails with 429, 500, 502, 503, 504
+	// or a network error, waiting ModioRetryBaseDelay (doubled per retry, with jitter) in between. A
+	// Retry-After from mod.io is honoured instead. 1 disables retries.
+	ModioRetryAttempts  int
+	ModioRetryBaseDelay time.Duration
+
+	// ModCategories are the mod.io tags indexed as mod types (Map and Script by default). Each gets its own
+	// list routes and full sync pass; its type name is the lowercased tag.
+	ModCategories []string
+
+	// FetchFilters holds, per category, extra mod.io filters sent with every /mods fetch of that type, read
+	// from MODIO_<CATEGORY>_FILTERS as a query string (e.g. "tags=Park&date_live-min=1600000000"). Mods
+	// they exclude are removed from the cache by the next full sync.
+	FetchFilters map[string]url.Values
 
 	// --- New Redis Config ---
 	RedisAddr     string
 	RedisPassword string // Leave empty if no password
 	RedisDB       int    // Default is 0
+
+	// RedisReadAddr optionally points API reads at a read-only replica (same password and DB).
+	// Writes and the scheduler always use RedisAddr.
+	RedisReadAddr string
+
+	// TrustedProxies lists the networks whose X-Forwarded-For/X-Real-IP headers are honored.
+	// Requests from any other address are attributed to the socket's remote address.
+	TrustedProxies []*net.IPNet
+
+	// RequireClientID makes the public data endpoints answer 400 to requests without an X-Client-ID
+	// header. With ClientIDs set, ids outside it are rejected with 403 as well.
+	RequireClientID bool
+	ClientIDs       map[string]bool
+
+	// CORSAllowedOrigins are the browser origins allowed to read responses cross-origin. Any origin is
+	// allowed when it is empty.
+	CORSAllowedOrigins map[string]bool
+
+	// AdminToken guards the /admin endpoints (sent as "Authorization: Bearer <token>").
+	// Admin endpoints are disabled when it is empty.
+	AdminToken string
+
+	// ModioWebhookSecret is the shared secret mod.io signs webhook payloads with. The webhook endpoint
+	// rejects every request while it is empty; event polling keeps running either way.
+	ModioWebhookSecret string
+
+	// NormalizeUnicode additionally folds index keys to NFKC and strips diacritics, so "Café" and
+	// "Cafe" share tag sets and autocomplete entries. Changing it requires a full sync to reindex.
+	NormalizeUnicode bool
+
+	// BlockReadyUntilSynced keeps /health/ready at 503 until the first full sync of this process completes.
+	// BlockDataUntilSynced does the same for the data routes.
+	BlockReadyUntilSynced bool
+	BlockDataUntilSynced  bool
+
+	// MaintenanceMode starts the API with data routes answering 503 (toggle at runtime via /admin/maintenance).
+	// MaintenanceRetryAfter is the Retry-After sent with those responses.
+	MaintenanceMode       bool
+	MaintenanceRetryAfter time.Duration
+
+	// CompressResponses gzip- or deflate-compresses response bodies of at least CompressMinBytes for clients
+	// that accept it.
+	CompressResponses bool
+	CompressMinBytes  int
+
+	// MetricsEnabled serves Prometheus metrics at /metrics, outside the admin token and client id checks.
+	MetricsEnabled bool
+
+	// IncludeNormalizedTags adds each tag's index form ("normalized") next to its mod.io name in responses.
+	IncludeNormalizedTags bool
+
+	// SubmitterListFields and SubmitterDetailFields choose how much of a mod's submitter list and detail
+	// responses show: all of it, the username only, or nothing. Stored mods always keep the full submitter.
+	SubmitterListFields   string
+	SubmitterDetailFields string
+
+	// Autocomplete limits. Requests carrying the admin token may ask for up to AutocompleteAdminMaxLimit.
+	AutocompleteDefaultLimit  int
+	AutocompleteMaxLimit      int
+	AutocompleteAdminMaxLimit int
+	// AutocompleteMinPrefixLength is the shortest prefix (in characters) that is actually searched.
+	AutocompleteMinPrefixLength int
+
+	// TagOptionsCacheTTL is how long mod.io's game tag schema is cached in Redis.
+	TagOptionsCacheTTL time.Duration
+
+	// MaxConcurrentRequestsPerIP caps in-flight data requests per client IP; zero disables the cap.
+	// Behind a proxy, set TRUSTED_PROXIES too or every client shares the proxy's IP.
+	MaxConcurrentRequestsPerIP int
+
+	// MaxSSESubscribers caps concurrent sync-completion SSE connections per instance.
+	MaxSSESubscribers int
+
+	// LiveFallthrough serves list requests for a type that has never been synced from mod.io directly,
+	// at the cost of extra API calls during a cold start.
+	LiveFallthrough bool
+
+	// StrictQueryParams makes list endpoints reject unrecognized query parameters with 400 instead of
+	// ignoring them. Clients can opt in or out per request with ?strict=.
+	StrictQueryParams bool
+
+	// AllowPartialResults lets list endpoints serve the mods that loaded when some Redis reads fail,
+	// reporting the dropped count, instead of failing the whole response.
+	AllowPartialResults bool
+
+	// IntegrityAuditInterval is how often a background audit samples the indexes and mod blobs for
+	// divergences; zero disables it. IntegrityAuditSampleSize bounds each sample.
+	IntegrityAuditInterval   time.Duration
+	IntegrityAuditSampleSize int
+
+	// IndexCommentCounts maintains a per-type sorted set of mods by comment count.
+	IndexCommentCounts bool
+
+	// MaxStoredDescriptionLength truncates longer descriptions (in characters) in stored mods and list
+	// responses; the full text is kept aside for the detail endpoint. Zero stores descriptions whole.
+	MaxStoredDescriptionLength int
+
+	// HotSortHalfLife and HotSortSubscriberWeight tune ?sort=hot: a mod's score drops by one doubling of
+	// popularity per half-life since its last update, and each subscriber counts as that many downloads.
+	// Only the HotSortCandidates most downloaded and most recently updated mods are ranked.
+	HotSortHalfLife         time.Duration
+	HotSortSubscriberWeight int
+	HotSortCandidates       int
+
+	// DownloadRefreshInterval is how often cached mods whose download links expire before the next tick are
+	// re-fetched, at most DownloadRefreshBatchSize (up to 100, one mod.io request) per tick. Zero disables it.
+	DownloadRefreshInterval  time.Duration
+	DownloadRefreshBatchSize int
+
+	// SkipUnchangedFullSync lets a scheduled full sync skip every type whose mod.io total and cached count
+	// both match the last full sync, checked with one single-item request per type. Edits that leave the
+	// totals alone are left to event polling.
+	SkipUnchangedFullSync bool
+
+	// EventSyncDebounce is how long an on-demand event sync waits so triggers arriving meanwhile share one
+	// run. Zero runs it as soon as the scheduler is free.
+	EventSyncDebounce time.Duration
+
+	// StoreModfileChangelogs keeps the current modfile's changelog in stored mods; detail responses
+	// include it and list responses never do. Turning it off saves space with large changelogs.
+	StoreModfileChangelogs bool
+
+	// UntypedMods is what syncs do with mods carrying none of the ModCategories tags: skip them, drop
+	// them (also removing a stored copy), or index them under an "other" type.
+	UntypedMods string
+
+	// TagIndexExclude matches tags that get no tag index set, such as per-version labels. Matched tags
+	// are still stored on the mod.
+	TagIndexExclude []*regexp.Regexp
+
+	// CacheWarmTargets are requested (best effort, in the background) after every sync that wrote data,
+	// e.g. a CDN purge API or the list endpoints' public URLs to repopulate the CDN. CacheWarmToken, when
+	// set, is sent to each as a bearer token.
+	CacheWarmTargets []CacheWarmTarget
+	CacheWarmToken   string
+
+	// Values records where each setting came from, in load order, with secrets redacted.
+	Values []ConfigValue
+	// UnrecognizedEnv lists set environment variables that look like settings (they share a prefix
+	// with a known one) but aren't read, which usually means a typo'd name.
+	UnrecognizedEnv []string
+}
+
+// CacheWarmTarget is one request made after a sync, see AppConfig.CacheWarmTargets.
+type CacheWarmTarget struct {
+	Method string
+	URL    string
+}
+
+// Submitter field projections, see AppConfig.SubmitterListFields.
+const (
+	SubmitterFieldsFull     = "full"
+	SubmitterFieldsUsername = "username"
+	SubmitterFieldsNone     = "none"
+)
+
+// UntypedMods strategies.
+const (
+	UntypedModsSkip  = "skip"
+	UntypedModsDrop  = "drop"
+	UntypedModsOther = "other"
+)
+
+const (
+	SourceEnvironment = "environment"
+	SourceDefault     = "default"
+	SourceInvalid     = "default (invalid environment value)"
+
+	redactedValue = "[redacted]"
+)
+
+// categoryPattern is what each MODIO_CATEGORIES entry must match, since it ends up in routes, Redis keys
+// and environment variable names.
+var categoryPattern = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+
+// gameSlugPattern is what MODIO_GAME_SLUG must match to be usable as a single path segment.
+var gameSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// ConfigValue describes one setting's effective value and where it came from.
+type ConfigValue struct {
+	Env    string `json:"env"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
 }
 
 func Load() *AppConfig {
+	l := &loader{}
 	cfg := &AppConfig{
-		ServerPort:               getEnv("PORT", "8000"),
-		ModioAPIKey:              os.Getenv("MODIO_API_KEY"), // Critical: No default
-		ModioGameID:              getEnv("MODIO_GAME_ID", "629"), // SkaterXL Game ID
-		ModioAPIDomain:           getEnv("MODIO_API_DOMAIN", "api.mod.io"), // Official domain
-		CacheRefreshInterval:     getEnvAsDurationHours("CACHE_REFRESH_INTERVAL_HOURS", 6*time.Hour),
-		LightweightCheckInterval: getEnvAsDurationMinutes("LIGHTWEIGHT_CHECK_INTERVAL_MINUTES", 15*time.Minute), // Check more frequently
+		ServerPort:               l.getEnv("PORT", "8000"),
+		ModioAPIKey:              l.getSecret("MODIO_API_KEY"),     // Critical: No default
+		ModioGameID:              l.getEnv("MODIO_GAME_ID", "629"), // SkaterXL Game ID
+		ModioGameSlug:            l.getEnv("MODIO_GAME_SLUG", "skaterxl"),
+		ModioAPIDomain:           l.getEnv("MODIO_API_DOMAIN", "api.mod.io"), // Official domain
+		ModioAPIVersion:          l.getEnv("MODIO_API_VERSION", "v1"),
+		VerifyGameOnStartup:      l.getEnvAsBool("VERIFY_GAME_ON_STARTUP", true),
+		CacheRefreshInterval:     l.getEnvAsDurationHours("CACHE_REFRESH_INTERVAL_HOURS", 6*time.Hour),
+		LightweightCheckInterval: l.getEnvAsDurationMinutes("LIGHTWEIGHT_CHECK_INTERVAL_MINUTES", 15*time.Minute), // Check more frequently
+		CatchUpThreshold:         l.getEnvAsDurationHours("CATCH_UP_THRESHOLD_HOURS", 24*time.Hour),
+		EventDedupWindow:         l.getEnvAsDurationMinutes("EVENT_DEDUP_WINDOW_MINUTES", 60*time.Minute),
+
+		EventCursorRepairLookback:   l.getEnvAsDurationMinutes("EVENT_CURSOR_REPAIR_LOOKBACK_MINUTES", 60*time.Minute),
+		EventCursorStaleThreshold:   l.getEnvAsDurationHours("EVENT_CURSOR_STALE_THRESHOLD_HOURS", 24*time.Hour),
+		EventDetailFetchConcurrency: l.getEnvAsInt("EVENT_DETAIL_FETCH_CONCURRENCY", 5),
+		AuthFailureCooldown:         l.getEnvAsDurationMinutes("AUTH_FAILURE_COOLDOWN_MINUTES", 60*time.Minute),
+		InitialSyncRetries:          l.getEnvAsInt("INITIAL_SYNC_RETRIES", 5),
+
+		ModioRecordDir: l.getEnv("MODIO_RECORD_DIR", ""), // Default: no recording
+		ModioReplayDir: l.getEnv("MODIO_REPLAY_DIR", ""), // Default: live mod.io API
+
+		ModioRetryAttempts:  l.getEnvAsInt("MODIO_RETRY_ATTEMPTS", 3),
+		ModioRetryBaseDelay: time.Duration(l.getEnvAsInt("MODIO_RETRY_BASE_DELAY_MS", 500)) * time.Millisecond,
+
+		ModCategories: l.getEnvAsCategories("MODIO_CATEGORIES", []string{"Map", "Script"}),
 
 		// --- Load Redis Config ---
-		RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),
-		RedisPassword: getEnv("REDIS_PASSWORD", ""), // Default to no password
-		RedisDB:       getEnvAsInt("REDIS_DB", 0),   // Default to DB 0
+		RedisAddr:     l.getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPassword: l.getSecret("REDIS_PASSWORD"),   // Default to no password
+		RedisDB:       l.getEnvAsInt("REDIS_DB", 0),    // Default to DB 0
+		RedisReadAddr: l.getEnv("REDIS_READ_ADDR", ""), // Default: read from the primary
+
+		TrustedProxies: l.getEnvAsCIDRList("TRUSTED_PROXIES"), // Default: trust no proxies
+		AdminToken:     l.getSecret("ADMIN_TOKEN"),            // No default: admin endpoints disabled
+
+		RequireClientID: l.getEnvAsBool("REQUIRE_CLIENT_ID", false),
+		ClientIDs:       l.getEnvAsSet("CLIENT_IDS"), // Default: accept any client id
+
+		CORSAllowedOrigins: l.getEnvAsSet("CORS_ALLOWED_ORIGINS"), // Default: allow any origin
+
+		ModioWebhookSecret: l.getSecret("MODIO_WEBHOOK_SECRET"), // No default: webhook ingestion disabled
+
+		NormalizeUnicode:      l.getEnvAsBool("NORMALIZE_UNICODE", false),
+		IncludeNormalizedTags: l.getEnvAsBool("INCLUDE_NORMALIZED_TAGS", false),
+		IndexCommentCounts:    l.getEnvAsBool("INDEX_COMMENT_COUNTS", false),
+		TagIndexExclude:       l.getEnvAsTagPatterns("TAG_INDEX_EXCLUDE"), // Default: index every tag
+		UntypedMods:           l.getEnv("UNTYPED_MODS", UntypedModsSkip),
+		LiveFallthrough:       l.getEnvAsBool("LIVE_FALLTHROUGH", false),
+		StrictQueryParams:     l.getEnvAsBool("STRICT_QUERY_PARAMS", false),
+		AllowPartialResults:   l.getEnvAsBool("ALLOW_PARTIAL_RESULTS", false),
+
+		SubmitterListFields:   l.getEnv("SUBMITTER_LIST_FIELDS", SubmitterFieldsFull),
+		SubmitterDetailFields: l.getEnv("SUBMITTER_DETAIL_FIELDS", SubmitterFieldsFull),
+
+		BlockReadyUntilSynced: l.getEnvAsBool("BLOCK_READY_UNTIL_SYNCED", false),
+		BlockDataUntilSynced:  l.getEnvAsBool("BLOCK_DATA_UNTIL_SYNCED", false),
+		MaintenanceMode:       l.getEnvAsBool("MAINTENANCE_MODE", false),
+		MaintenanceRetryAfter: l.getEnvAsDurationMinutes("MAINTENANCE_RETRY_AFTER_MINUTES", 5*time.Minute),
+		MaxSSESubscribers:     l.getEnvAsInt("MAX_SSE_SUBSCRIBERS", 100),
+
+		MetricsEnabled: l.getEnvAsBool("METRICS_ENABLED", true),
+
+		CompressResponses: l.getEnvAsBool("COMPRESS_RESPONSES", true),
+		CompressMinBytes:  l.getEnvAsInt("COMPRESS_MIN_BYTES", 1024),
+
+		MaxConcurrentRequestsPerIP: l.getEnvAsInt("MAX_CONCURRENT_REQUESTS_PER_IP", 0), // Default: unlimited
+		MaxStoredDescriptionLength: l.getEnvAsInt("MAX_STORED_DESCRIPTION_LENGTH", 0),  // Default: no truncation
+		StoreModfileChangelogs:     l.getEnvAsBool("STORE_MODFILE_CHANGELOGS", true),
+		TagOptionsCacheTTL:         l.getEnvAsDurationMinutes("TAG_OPTIONS_CACHE_MINUTES", 60*time.Minute),
+
+		AutocompleteDefaultLimit:  l.getEnvAsInt("AUTOCOMPLETE_DEFAULT_LIMIT", 10),
+		AutocompleteMaxLimit:      l.getEnvAsInt("AUTOCOMPLETE_MAX_LIMIT", 50),
+		AutocompleteAdminMaxLimit: l.getEnvAsInt("AUTOCOMPLETE_ADMIN_MAX_LIMIT", 500),
+
+		AutocompleteMinPrefixLength: l.getEnvAsInt("AUTOCOMPLETE_MIN_PREFIX_LENGTH", 2),
+
+		IntegrityAuditInterval:   l.getEnvAsDurationHours("INTEGRITY_AUDIT_INTERVAL_HOURS", 0), // Default: disabled
+		IntegrityAuditSampleSize: l.getEnvAsInt("INTEGRITY_AUDIT_SAMPLE_SIZE", 500),
+
+		HotSortHalfLife:         l.getEnvAsDurationHours("HOT_SORT_HALF_LIFE_HOURS", 48*time.Hour),
+		HotSortSubscriberWeight: l.getEnvAsInt("HOT_SORT_SUBSCRIBER_WEIGHT", 5),
+		HotSortCandidates:       l.getEnvAsInt("HOT_SORT_CANDIDATES", 500),
+
+		DownloadRefreshInterval:  time.Duration(l.getEnvAsInt("DOWNLOAD_REFRESH_INTERVAL_MINUTES", 0)) * time.Minute, // Default: disabled
+		DownloadRefreshBatchSize: l.getEnvAsInt("DOWNLOAD_REFRESH_BATCH_SIZE", 20),
+
+		SkipUnchangedFullSync: l.getEnvAsBool("FULL_SYNC_SKIP_UNCHANGED", false),
+
+		EventSyncDebounce: time.Duration(l.getEnvAsInt("EVENT_SYNC_DEBOUNCE_SECONDS", 10)) * time.Second,
+
+		CacheWarmTargets: l.getEnvAsWarmTargets("CACHE_WARM_URLS"), // Default: no post-sync requests
+		CacheWarmToken:   l.getSecret("CACHE_WARM_TOKEN"),
+	}
+
+	cfg.FetchFilters = make(map[string]url.Values, len(cfg.ModCategories))
+	for _, category := range cfg.ModCategories {
+		cfg.FetchFilters[category] = l.getEnvAsQuery("MODIO_" + strings.ToUpper(category) + "_FILTERS") // Default: no extra filters
+	}
+
+	cfg.Values = l.values
+	cfg.UnrecognizedEnv = unrecognizedEnv(l.values)
+
+	if cfg.AutocompleteMaxLimit < 1 {
+		log.Printf("Warning: AUTOCOMPLETE_MAX_LIMIT must be positive, got %d. Using 50.", cfg.AutocompleteMaxLimit)
+		cfg.AutocompleteMaxLimit = 50
+	}
+	if cfg.AutocompleteAdminMaxLimit < cfg.AutocompleteMaxLimit {
+		cfg.AutocompleteAdminMaxLimit = cfg.AutocompleteMaxLimit // Admins never get less than the public cap
+	}
+	if cfg.AutocompleteDefaultLimit < 1 || cfg.AutocompleteDefaultLimit > cfg.AutocompleteMaxLimit {
+		log.Printf("Warning: AUTOCOMPLETE_DEFAULT_LIMIT must be between 1 and %d, got %d. Clamping.", cfg.AutocompleteMaxLimit, cfg.AutocompleteDefaultLimit)
+		cfg.AutocompleteDefaultLimit = max(1, min(cfg.AutocompleteDefaultLimit, cfg.AutocompleteMaxLimit))
 	}
 
-	if cfg.ModioAPIKey == "" {
+	for _, setting := range []struct {
+		name  string
+		value *string
+	}{{"SUBMITTER_LIST_FIELDS", &cfg.SubmitterListFields}, {"SUBMITTER_DETAIL_FIELDS", &cfg.SubmitterDetailFields}} {
+		switch *setting.value {
+		case SubmitterFieldsFull, SubmitterFieldsUsername, SubmitterFieldsNone:
+		default:
+			log.Printf("Warning: %s must be full, username or none, got %q. Using full.", setting.name, *setting.value)
+			*setting.value = SubmitterFieldsFull
+		}
+	}
+	switch cfg.UntypedMods {
+	case UntypedModsSkip, UntypedModsDrop, UntypedModsOther:
+	default:
+		log.Printf("Warning: UNTYPED_MODS must be skip, drop or other, got %q. Using skip.", cfg.UntypedMods)
+		cfg.UntypedMods = UntypedModsSkip
+	}
+	if !gameSlugPattern.MatchString(cfg.ModioGameSlug) {
+		log.Printf("Warning: MODIO_GAME_SLUG must be lowercase letters, digits and dashes, got %q. Using skaterxl.", cfg.ModioGameSlug)
+		cfg.ModioGameSlug = "skaterxl"
+	}
+	if cfg.ModioRetryAttempts < 1 {
+		log.Printf("Warning: MODIO_RETRY_ATTEMPTS must be at least 1, got %d. Using 1.", cfg.ModioRetryAttempts)
+		cfg.ModioRetryAttempts = 1
+	}
+	if cfg.ModioRetryBaseDelay < 0 {
+		log.Printf("Warning: MODIO_RETRY_BASE_DELAY_MS must not be negative, got %d. Using 500.", int(cfg.ModioRetryBaseDelay/time.Millisecond))
+		cfg.ModioRetryBaseDelay = 500 * time.Millisecond
+	}
+	if cfg.EventDetailFetchConcurrency < 1 {
+		log.Printf("Warning: EVENT_DETAIL_FETCH_CONCURRENCY must be at least 1, got %d. Using 1.", cfg.EventDetailFetchConcurrency)
+		cfg.EventDetailFetchConcurrency = 1
+	}
+	if cfg.InitialSyncRetries < 0 {
+		log.Printf("Warning: INITIAL_SYNC_RETRIES must not be negative, got %d. Using 0.", cfg.InitialSyncRetries)
+		cfg.InitialSyncRetries = 0
+	}
+	if cfg.HotSortSubscriberWeight < 0 {
+		log.Printf("Warning: HOT_SORT_SUBSCRIBER_WEIGHT must not be negative, got %d. Using 5.", cfg.HotSortSubscriberWeight)
+		cfg.HotSortSubscriberWeight = 5
+	}
+	if cfg.HotSortCandidates <= 0 {
+		log.Printf("Warning: HOT_SORT_CANDIDATES must be positive, got %d. Using 500.", cfg.HotSortCandidates)
+		cfg.HotSortCandidates = 500
+	}
+	if cfg.DownloadRefreshInterval < 0 {
+		log.Printf("Warning: DOWNLOAD_REFRESH_INTERVAL_MINUTES must not be negative, got %d. Disabling.", int(cfg.DownloadRefreshInterval/time.Minute))
+		cfg.DownloadRefreshInterval = 0
+	}
+	if cfg.DownloadRefreshBatchSize < 1 || cfg.DownloadRefreshBatchSize > 100 {
+		log.Printf("Warning: DOWNLOAD_REFRESH_BATCH_SIZE must be between 1 and 100, got %d. Clamping.", cfg.DownloadRefreshBatchSize)
+		cfg.DownloadRefreshBatchSize = max(1, min(cfg.DownloadRefreshBatchSize, 100))
+	}
+	if cfg.EventSyncDebounce < 0 {
+		log.Printf("Warning: EVENT_SYNC_DEBOUNCE_SECONDS must not be negative, got %d. Using 10.", int(cfg.EventSyncDebounce/time.Second))
+		cfg.EventSyncDebounce = 10 * time.Second
+	}
+	if cfg.CompressMinBytes < 0 {
+		log.Printf("Warning: COMPRESS_MIN_BYTES must not be negative, got %d. Using 1024.", cfg.CompressMinBytes)
+		cfg.CompressMinBytes = 1024
+	}
+	if cfg.IntegrityAuditSampleSize < 1 {
+		log.Printf("Warning: INTEGRITY_AUDIT_SAMPLE_SIZE must be positive, got %d. Using 500.", cfg.IntegrityAuditSampleSize)
+		cfg.IntegrityAuditSampleSize = 500
+	}
+
+	if cfg.ModioAPIKey == "" && cfg.ModioReplayDir == "" {
 		log.Fatal("FATAL ERROR: MODIO_API_KEY environment variable is not set. Application cannot start.")
 	}
 	return cfg
 }
 
-func getEnv(key, fallback string) string {
+// loader reads settings from the environment and records each one's source.
+type loader struct {
+	values []ConfigValue
+}
+
+func (l *loader) record(key, value, source string) {
+	l.values = append(l.values, ConfigValue{Env: key, Value: value, Source: source})
+}
+
+func (l *loader) getEnv(key, fallback string) string {
 	if value, exists := os.LookupEnv(key); exists {
+		l.record(key, value, SourceEnvironment)
 		return value
 	}
+	l.record(key, fallback, SourceDefault)
 	return fallback
 }
 
-func getEnvAsDurationHours(key string, fallback time.Duration) time.Duration { // Renamed from getEnvAsDuration
-	strValue := getEnv(key, "")
+// getSecret reads a setting without a default whose value must never be exposed.
+func (l *loader) getSecret(key string) string {
+	value, exists := os.LookupEnv(key)
+	switch {
+	case exists && value != "":
+		l.record(key, redactedValue, SourceEnvironment)
+	case exists:
+		l.record(key, "", SourceEnvironment)
+	default:
+		l.record(key, "", SourceDefault)
+	}
+	return value
+}
+
+func (l *loader) getEnvAsDurationHours(key string, fallback time.Duration) time.Duration { // Renamed from getEnvAsDuration
+	strValue := os.Getenv(key)
 	if strValue != "" {
 		if hours, err := strconv.Atoi(strValue); err == nil {
 			if hours > 0 {
+				l.record(key, strValue, SourceEnvironment)
 				return time.Duration(hours) * time.Hour
 			}
 			log.Printf("Warning: Invalid non-positive value for %s (hours): %s. Using default.", key, strValue)
 		} else {
 			log.Printf("Warning: Invalid integer format for %s (hours): %s. Using default.", key, strValue)
 		}
+		l.record(key, strconv.Itoa(int(fallback/time.Hour)), SourceInvalid)
+		return fallback
 	}
+	l.record(key, strconv.Itoa(int(fallback/time.Hour)), SourceDefault)
 	return fallback
 }
 
-func getEnvAsDurationMinutes(key string, fallback time.Duration) time.Duration {
-	strValue := getEnv(key, "")
+func (l *loader) getEnvAsDurationMinutes(key string, fallback time.Duration) time.Duration {
+	strValue := os.Getenv(key)
 	if strValue != "" {
 		if minutes, err := strconv.Atoi(strValue); err == nil {
 			if minutes > 0 {
+				l.record(key, strValue, SourceEnvironment)
 				return time.Duration(minutes) * time.Minute
 			}
 			log.Printf("Warning: Invalid non-positive value for %s (minutes): %s. Using default.", key, strValue)
 		} else {
 			log.Printf("Warning: Invalid integer format for %s (minutes): %s. Using default.", key, strValue)
 		}
+		l.record(key, strconv.Itoa(int(fallback/time.Minute)), SourceInvalid)
+		return fallback
 	}
+	l.record(key, strconv.Itoa(int(fallback/time.Minute)), SourceDefault)
 	return fallback
 }
 
-func getEnvAsInt(key string, fallback int) int {
-	strValue := getEnv(key, "")
+func (l *loader) getEnvAsInt(key string, fallback int) int {
+	strValue := os.Getenv(key)
 	if strValue != "" {
 		if intVal, err := strconv.Atoi(strValue); err == nil {
+			l.record(key, strValue, SourceEnvironment)
 			return intVal
 		}
 		log.Printf("Warning: Invalid integer format for %s: %s. Using default.", key, strValue)
+		l.record(key, strconv.Itoa(fallback), SourceInvalid)
+		return fallback
 	}
+	l.record(key, strconv.Itoa(fallback), SourceDefault)
 	return fallback
-}
\ No newline at end of file
+}
+
+func (l *loader) getEnvAsBool(key string, fallback bool) bool {
+	strValue := os.Getenv(key)
+	if strValue != "" {
+		if boolVal, err := strconv.ParseBool(strValue); err == nil {
+			l.record(key, strconv.FormatBool(boolVal), SourceEnvironment)
+			return boolVal
+		}
+		log.Printf("Warning: Invalid boolean format for %s: %s. Using default.", key, strValue)
+		l.record(key, strconv.FormatBool(fallback), SourceInvalid)
+		return fallback
+	}
+	l.record(key, strconv.FormatBool(fallback), SourceDefault)
+	return fallback
+}
+
+// getEnvAsCIDRList parses a comma-separated list of CIDRs. Bare IPs are accepted as single-host networks.
+func (l *loader) getEnvAsCIDRList(key string) []*net.IPNet {
+	strValue := os.Getenv(key)
+	if strValue == "" {
+		l.record(key, "", SourceDefault)
+		return nil
+	}
+	var networks []*net.IPNet
+	for _, entry := range strings.Split(strValue, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				log.Printf("Warning: Invalid IP in %s: %s. Skipping.", key, entry)
+				continue
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Printf("Warning: Invalid CIDR in %s: %s. Skipping.", key, entry)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	l.record(key, strValue, SourceEnvironment)
+	return networks
+}
+
+// getEnvAsSet parses a comma-separated list into a set, ignoring empty entries.
+func (l *loader) getEnvAsSet(key string) map[string]bool {
+	strValue := os.Getenv(key)
+	if strValue == "" {
+		l.record(key, "", SourceDefault)
+		return nil
+	}
+	set := make(map[string]bool)
+	for _, entry := range strings.Split(strValue, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			set[entry] = true
+		}
+	}
+	l.record(key, strValue, SourceEnvironment)
+	return set
+}
+
+// getEnvAsCategories parses a comma-separated list of category tags, keeping their order. Invalid entries,
+// case-insensitive duplicates and "Other" (reserved for UNTYPED_MODS=other) are skipped; an empty result
+// falls back to the default.
+func (l *loader) getEnvAsCategories(key string, fallback []string) []string {
+	strValue := os.Getenv(key)
+	if strValue == "" {
+		l.record(key, strings.Join(fallback, ","), SourceDefault)
+		return fallback
+	}
+	var categories []string
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(strValue, ",") {
+		entry = strings.TrimSpace(entry)
+		switch lower := strings.ToLower(entry); {
+		case entry == "":
+		case !categoryPattern.MatchString(entry) || lower == "other":
+			log.Printf("Warning: Invalid category in %s: %s. Skipping.", key, entry)
+		case !seen[lower]:
+			seen[lower] = true
+			categories = append(categories, entry)
+		}
+	}
+	if len(categories) == 0 {
+		log.Printf("Warning: No valid categories in %s: %s. Using default.", key, strValue)
+		l.record(key, strings.Join(fallback, ","), SourceInvalid)
+		return fallback
+	}
+	l.record(key, strValue, SourceEnvironment)
+	return categories
+}
+
+// getEnvAsTagPatterns parses a comma-separated list of tag patterns. An entry wrapped in slashes is a
+// regular expression, anything else a prefix; both match case-insensitively.
+func (l *loader) getEnvAsTagPatterns(key string) []*regexp.Regexp {
+	strValue := os.Getenv(key)
+	if strValue == "" {
+		l.record(key, "", SourceDefault)
+		return nil
+	}
+	var patterns []*regexp.Regexp
+	for _, entry := range strings.Split(strValue, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		expr := "^" + regexp.QuoteMeta(entry)
+		if len(entry) > 2 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/") {
+			expr = entry[1 : len(entry)-1]
+		}
+		pattern, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			log.Printf("Warning: Invalid tag pattern in %s: %s. Skipping.", key, entry)
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	l.record(key, strValue, SourceEnvironment)
+	return patterns
+}
+
+// getEnvAsWarmTargets parses a comma-separated list of URLs, each optionally preceded by an HTTP method
+// and a space ("POST https://cdn.example/purge"). The method defaults to GET.
+func (l *loader) getEnvAsWarmTargets(key string) []CacheWarmTarget {
+	strValue := os.Getenv(key)
+	if strValue == "" {
+		l.record(key, "", SourceDefault)
+		return nil
+	}
+	var targets []CacheWarmTarget
+	for _, entry := range strings.Split(strValue, ",") {
+		target := CacheWarmTarget{Method: http.MethodGet, URL: strings.TrimSpace(entry)}
+		if method, rawURL, found := strings.Cut(target.URL, " "); found {
+			target.Method, target.URL = strings.ToUpper(method), strings.TrimSpace(rawURL)
+		}
+		if target.URL == "" {
+			continue
+		}
+		if parsed, err := url.Parse(target.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			log.Printf("Warning: Invalid URL in %s: %s. Skipping.", key, target.URL)
+			continue
+		}
+		targets = append(targets, target)
+	}
+	l.record(key, strValue, SourceEnvironment)
+	return targets
+}
+
+// getEnvAsQuery parses a URL query string. An unparsable value is ignored rather than half-applied.
+func (l *loader) getEnvAsQuery(key string) url.Values {
+	strValue := os.Getenv(key)
+	if strValue == "" {
+		l.record(key, "", SourceDefault)
+		return nil
+	}
+	values, err := url.ParseQuery(strValue)
+	if err != nil {
+		log.Printf("Warning: Invalid query string for %s: %s. Using default.", key, strValue)
+		l.record(key, "", SourceInvalid)
+		return nil
+	}
+	l.record(key, values.Encode(), SourceEnvironment)
+	return values
+}
+
+// unrecognizedEnv returns set environment variables that share their first underscore-separated segment
+// with a known setting (e.g. REDIS_ADRR next to REDIS_ADDR) but are not known themselves.
+func unrecognizedEnv(values []ConfigValue) []string {
+	known := make(map[string]bool, len(values))
+	prefixes := make(map[string]bool)
+	for _, v := range values {
+		known[v.Env] = true
+		if prefix, _, ok := strings.Cut(v.Env, "_"); ok {
+			prefixes[prefix] = true
+		}
+	}
+	var unrecognized []string
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		prefix, _, ok := strings.Cut(name, "_")
+		if ok && prefixes[prefix] && !known[name] {
+			unrecognized = append(unrecognized, name)
+		}
+	}
+	sort.Strings(unrecognized)
+	return unrecognized
+}
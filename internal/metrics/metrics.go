@@ -0,0 +1,135 @@
+// Package metrics holds the Prometheus collectors shared by the scheduler, the mod.io client, the
+// repository and the HTTP layer, all registered on Registry and served by Handler.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "modio_api"
+
+// Version is reported by the build info gauge. Set it at build time with
+// -ldflags "-X github.com/ShawnEdgell/modio-api-go/internal/metrics.Version=v1.2.3"; otherwise the module
+// version from the build info is used.
+var Version = ""
+
+// Registry holds every collector of this package plus the Go runtime and process collectors.
+var Registry = prometheus.NewRegistry()
+
+var (
+	FullSyncs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Name: "full_syncs_total", Help: "Full synchronizations run, by result.",
+	}, []string{"result"})
+	FullSyncDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace, Name: "full_sync_duration_seconds", Help: "Duration of full synchronizations.",
+		Buckets: []float64{5, 15, 30, 60, 120, 300, 600, 1200},
+	})
+	EventSyncs = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace, Name: "event_syncs_total", Help: "Event processing cycles run.",
+	})
+	EventSyncDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace, Name: "event_sync_duration_seconds", Help: "Duration of event processing cycles.",
+		Buckets: prometheus.ExponentialBuckets(0.1, 2, 10),
+	})
+	ModsFetched = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace, Name: "full_sync_mods_fetched", Help: "Mods fetched from mod.io by the last full sync of each type.",
+	}, []string{"type"})
+	ModioRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Name: "modio_requests_total", Help: "Requests made to mod.io, by status code (\"error\" when no response arrived).",
+	}, []string{"code"})
+	RedisPipelineSize = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace, Name: "redis_pipeline_commands", Help: "Commands per executed change set pipeline.",
+		Buckets: prometheus.ExponentialBuckets(10, 4, 8),
+	})
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Name: "http_requests_total", Help: "HTTP requests served, by route pattern, method and status.",
+	}, []string{"route", "method", "code"})
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Name: "http_request_duration_seconds", Help: "HTTP request latency, by route pattern and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+)
+
+func init() {
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace, Name: "build_info", Help: "Always 1; labelled with the build's version and Go version.",
+		ConstLabels: prometheus.Labels{"version": version(), "go_version": runtime.Version()},
+	})
+	buildInfo.Set(1)
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		buildInfo,
+		FullSyncs, FullSyncDuration, EventSyncs, EventSyncDuration, ModsFetched,
+		ModioRequests, RedisPipelineSize, HTTPRequests, HTTPRequestDuration,
+	)
+}
+
+func version() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "unknown"
+}
+
+// Handler serves Registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveFullSync records a finished full synchronization that started at start.
+func ObserveFullSync(start time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	FullSyncs.WithLabelValues(result).Inc()
+	FullSyncDuration.Observe(time.Since(start).Seconds())
+}
+
+// ObserveEventSync records a finished event processing cycle that started at start.
+func ObserveEventSync(start time.Time) {
+	EventSyncs.Inc()
+	EventSyncDuration.Observe(time.Since(start).Seconds())
+}
+
+// ObserveModioResponse counts a mod.io request by the status code of resp, or as "error" when err is set.
+func ObserveModioResponse(resp *http.Response, err error) {
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	ModioRequests.WithLabelValues(code).Inc()
+}
+
+// RegisterCounterFunc exports a counter kept elsewhere, read from value at every scrape. Registering the
+// same name twice keeps the first registration.
+func RegisterCounterFunc(name, help string, value func() float64) {
+	registerOnce(prometheus.NewCounterFunc(prometheus.CounterOpts{Namespace: namespace, Name: name, Help: help}, value))
+}
+
+// RegisterGaugeFunc is RegisterCounterFunc for a value that can go down.
+func RegisterGaugeFunc(name, help string, value func() float64) {
+	registerOnce(prometheus.NewGaugeFunc(prometheus.GaugeOpts{Namespace: namespace, Name: name, Help: help}, value))
+}
+
+func registerOnce(collector prometheus.Collector) {
+	if err := Registry.Register(collector); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if !errors.As(err, &already) {
+			panic(err)
+		}
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/ShawnEdgell/modio-api-go/internal/config"
@@ -18,25 +21,80 @@ const (
 	defaultSort    = "-date_updated"
 	requestTimeout = 20 * time.Second
 	requestDelay   = 500 * time.Millisecond
+
+	modfilePageSafeguard = 10  // Caps a mod's file history (and dependency list) at 1000 entries
+	absolutePageCap      = 200 // Upper bound on FetchAllItems pages whatever safeguard the caller passes
 )
 
 type Client struct {
 	httpClient *http.Client
-	apiKey     string
+	apiKey     atomic.Pointer[string] // Swapped by SetAPIKey while requests are in flight
 	gameID     string
 	apiDomain  string
+	apiVersion string                // Path segment of the API version, e.g. "v1"
+	filters    map[string]url.Values // Extra /mods filters per type tag
+
+	gameInfo atomic.Pointer[ModioGame] // Last successful GetGameInfo result
+	retry    retryPolicy
+	retries  atomic.Int64 // Retries made by withRetry, see Retries
+
+	rateLimit atomic.Pointer[RateLimitStatus] // Last reported by mod.io, see RateLimitStatus
 }
 
+// reservedParams are query parameters the client sets itself; fetch filters may not override them.
+var reservedParams = map[string]bool{"api_key": true, "tags-in": true, "_sort": true, "_limit": true, "_offset": true}
+
 func NewClient(cfg *config.AppConfig) (*Client, error) {
-	if cfg.ModioAPIKey == "" {
+	httpClient := &http.Client{Timeout: requestTimeout}
+	switch {
+	case cfg.ModioReplayDir != "":
+		slog.Warn("Serving mod.io responses from recordings", "dir", cfg.ModioReplayDir)
+		httpClient.Transport = NewReplayTransport(cfg.ModioReplayDir)
+	case cfg.ModioAPIKey == "":
 		return nil, fmt.Errorf("mod.io API key is not configured")
+	case cfg.ModioRecordDir != "":
+		slog.Warn("Recording mod.io requests and responses", "dir", cfg.ModioRecordDir)
+		httpClient.Transport = NewRecordingTransport(cfg.ModioRecordDir, nil)
 	}
-	return &Client{
-		httpClient: &http.Client{Timeout: requestTimeout},
-		apiKey:     cfg.ModioAPIKey,
+	httpClient.Transport = countingTransport{next: httpClient.Transport}
+	client := &Client{
+		httpClient: httpClient,
 		gameID:     cfg.ModioGameID,
 		apiDomain:  cfg.ModioAPIDomain,
-	}, nil
+		apiVersion: strings.Trim(cfg.ModioAPIVersion, "/"),
+		retry:      retryPolicy{attempts: max(cfg.ModioRetryAttempts, 1), baseDelay: cfg.ModioRetryBaseDelay},
+		filters:    cfg.FetchFilters,
+	}
+	apiKey := cfg.ModioAPIKey
+	client.apiKey.Store(&apiKey)
+	return client, nil
+}
+
+// SetAPIKey replaces the API key used by subsequent requests; requests already sent keep the old one.
+func (c *Client) SetAPIKey(apiKey string) error {
+	if apiKey == "" {
+		return fmt.Errorf("mod.io API key must not be empty")
+	}
+	c.apiKey.Store(&apiKey)
+	slog.Warn("mod.io API key rotated")
+	return nil
+}
+
+// gamePath returns the request path of a game resource under the configured API version.
+func (c *Client) gamePath(resource string) string {
+	return "/" + c.apiVersion + "/games/" + c.gameID + resource
+}
+
+// addTypeFilters adds the configured filters for itemTypeTag to queryParams. Multiple values for one
+// filter are sent as a single comma-separated value, mod.io's list syntax.
+func (c *Client) addTypeFilters(queryParams url.Values, itemTypeTag string) {
+	for key, values := range c.filters[itemTypeTag] {
+		if reservedParams[key] {
+			slog.Warn("Ignoring fetch filter that would override a client-set parameter", "type_tag", itemTypeTag, "filter", key)
+			continue
+		}
+		queryParams.Set(key, strings.Join(values, ","))
+	}
 }
 
 func (c *Client) fetchGenericPaginatedData(ctx context.Context, path string, queryParams url.Values, responsePayload interface{}) error {
@@ -44,7 +102,7 @@ func (c *Client) fetchGenericPaginatedData(ctx context.Context, path string, que
 	for k, v := range queryParams { // Copy to avoid modifying caller's params map
 		actualParams[k] = v
 	}
-	actualParams.Add("api_key", c.apiKey)
+	actualParams.Add("api_key", *c.apiKey.Load())
 
 	u := url.URL{
 		Scheme:   "https",
@@ -61,6 +119,41 @@ func (c *Client) fetchGenericPaginatedData(ctx context.Context, path string, que
 	}
 	slog.Debug("Preparing to fetch from Mod.io", "url_path", u.Path, "params_for_log", loggingParams.Encode())
 
+	return c.withRetry(ctx, u.Path, func() error {
+		return c.getJSON(ctx, u, responsePayload)
+	})
+}
+
+// withRetry runs request until it succeeds, fails with an error that isn't retryable, or runs out of
+// attempts, waiting between tries as the retry policy says. Every try first waits out a nearly used up
+// rate limit.
+func (c *Client) withRetry(ctx context.Context, path string, request func() error) error {
+	for attempt := 1; ; attempt++ {
+		if pause := c.rateLimitPause(); pause > 0 {
+			slog.Warn("Mod.io rate limit nearly used up, waiting before the next request", "url_path", path, "delay", pause)
+			select {
+			case <-time.After(pause):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		err := request()
+		if err == nil || attempt >= c.retry.attempts || !retryable(ctx, err) {
+			return err
+		}
+		delay := c.retry.delay(attempt, err)
+		c.retries.Add(1)
+		slog.Warn("Retrying Mod.io request after a transient failure", "url_path", path, "attempt", attempt, "max_attempts", c.retry.attempts, "delay", delay, "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// getJSON makes a single GET request and decodes a 200 response into responsePayload.
+func (c *Client) getJSON(ctx context.Context, u url.URL, responsePayload interface{}) error {
 	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request for %s: %w", u.Path, err)
@@ -69,47 +162,79 @@ func (c *Client) fetchGenericPaginatedData(ctx context.Context, path string, que
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to make GET request to %s: %w", u.Path, err)
+		return fmt.Errorf("failed to make GET request to %s: %w", u.Path, &transportError{err})
 	}
 	defer resp.Body.Close()
+	c.noteRateLimit(resp)
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("mod.io API request to %s failed with status %s", u.Path, resp.Status)
+		return newAPIError(u.Path, resp)
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(responsePayload); err != nil {
+		if isTruncatedBody(err) {
+			err = &transportError{err}
+		}
 		return fmt.Errorf("failed to decode JSON response from %s: %w", u.Path, err)
 	}
 	return nil
 }
 
+// Retries returns how many mod.io requests have been retried after a transient failure.
+func (c *Client) Retries() int64 {
+	return c.retries.Load()
+}
+
+// PageProgress is reported after each page fetched by FetchAllItemsWithProgress.
+type PageProgress struct {
+	Page         int // 1-based number of the page just fetched
+	ItemsFetched int // Items fetched so far across all pages
+	ResultTotal  int // Total matching items reported by mod.io
+}
+
 func (c *Client) FetchAllItems(ctx context.Context, itemTypeTag string, maxPagesToFetch int) ([]Mod, error) {
+	return c.FetchAllItemsWithProgress(ctx, itemTypeTag, maxPagesToFetch, nil)
+}
+
+// FetchAllItemsWithProgress is FetchAllItems with an optional callback invoked after every page.
+func (c *Client) FetchAllItemsWithProgress(ctx context.Context, itemTypeTag string, maxPagesToFetch int, onPage func(PageProgress)) ([]Mod, error) {
 	var allItems []Mod
-	path := fmt.Sprintf("/v1/games/%s/mods", c.gameID)
-	slog.Info("Starting to fetch all items from Mod.io", "type_tag", itemTypeTag, "max_pages_limit", maxPagesToFetch, "path", path)
+	filters := c.TypeFilters(itemTypeTag)
+	slog.Info("Starting to fetch all items from Mod.io", "type_tag", itemTypeTag, "max_pages_limit", maxPagesToFetch)
 
+	maxPagesToFetch = min(maxPagesToFetch, absolutePageCap)
+	seenIDs := make(map[int]bool)
 	for page := 0; page < maxPagesToFetch; page++ {
-		currentOffset := page * apiPageSize
-		queryParams := url.Values{}
-		if itemTypeTag != "" {
-			queryParams.Add("tags-in", itemTypeTag)
-		}
-		queryParams.Add("_sort", defaultSort)
-		queryParams.Add("_limit", strconv.Itoa(apiPageSize))
-		queryParams.Add("_offset", strconv.Itoa(currentOffset))
-
 		slog.Debug("Fetching page for Mod.io items", "type_tag", itemTypeTag, "page_number", page+1)
 
-		var apiResponse ModioAPIResponse
-		err := c.fetchGenericPaginatedData(ctx, path, queryParams, &apiResponse)
+		apiResponse, err := c.FetchModsPage(ctx, filters, page*apiPageSize, apiPageSize)
 		if err != nil {
+			if ctx.Err() != nil {
+				slog.Warn("Fetching a page from Mod.io was stopped by the caller's context", "type_tag", itemTypeTag, "page_number", page+1, "error", err)
+				return nil, fmt.Errorf("fetch of page %d for type %s interrupted: %w", page+1, itemTypeTag, ctx.Err())
+			}
 			slog.Error("Failed to fetch a page from Mod.io", "type_tag", itemTypeTag, "page_number", page+1, "error", err)
 			return nil, fmt.Errorf("failed to fetch page %d for type %s: %w", page+1, itemTypeTag, err)
 		}
 
+		newItems := 0
+		for i := range apiResponse.Data {
+			if !seenIDs[apiResponse.Data[i].ID] {
+				seenIDs[apiResponse.Data[i].ID] = true
+				newItems++
+			}
+		}
+		if len(apiResponse.Data) > 0 && newItems == 0 {
+			// Returning the partial list would let a full sync delete every mod it didn't reach.
+			slog.Error("Mod.io pagination made no progress, a page repeated only known mods", "type_tag", itemTypeTag, "page_number", page+1)
+			return nil, fmt.Errorf("pagination for type %s made no progress at page %d", itemTypeTag, page+1)
+		}
 		if len(apiResponse.Data) > 0 {
 			allItems = append(allItems, apiResponse.Data...)
 		}
+		if onPage != nil {
+			onPage(PageProgress{Page: page + 1, ItemsFetched: len(allItems), ResultTotal: apiResponse.ResultTotal})
+		}
 
 		if len(apiResponse.Data) < apiPageSize || apiResponse.ResultCount < apiPageSize {
 			slog.Info("Fetched last page for items or API limit reached", "type_tag", itemTypeTag, "items_on_this_page", len(apiResponse.Data), "api_result_count", apiResponse.ResultCount)
@@ -117,9 +242,10 @@ func (c *Client) FetchAllItems(ctx context.Context, itemTypeTag string, maxPages
 		}
 
 		if page < maxPagesToFetch-1 {
-			slog.Debug("Sleeping between Mod.io paged requests", "duration", requestDelay)
+			delay := c.pageDelay()
+			slog.Debug("Sleeping between Mod.io paged requests", "duration", delay)
 			select {
-			case <-time.After(requestDelay):
+			case <-time.After(delay):
 			case <-ctx.Done():
 				return allItems, ctx.Err()
 			}
@@ -129,20 +255,53 @@ func (c *Client) FetchAllItems(ctx context.Context, itemTypeTag string, maxPages
 	return allItems, nil
 }
 
-func (c *Client) CheckForNewerMods(ctx context.Context, itemTypeTag string, sinceTimestamp int64) (bool, error) {
-	slog.Debug("Checking for newer mods via /mods endpoint", "type_tag", itemTypeTag, "since_timestamp", sinceTimestamp)
-	path := fmt.Sprintf("/v1/games/%s/mods", c.gameID)
-	queryParams := url.Values{}
+// TypeFilters returns the filters selecting a type's mods: the type tag, the configured fetch filters
+// and the default sort. Callers may add to or override them before passing them to FetchModsPage.
+func (c *Client) TypeFilters(itemTypeTag string) url.Values {
+	filters := url.Values{}
 	if itemTypeTag != "" {
-		queryParams.Add("tags-in", itemTypeTag)
+		filters.Add("tags-in", itemTypeTag)
 	}
-	queryParams.Add("date_updated-min", strconv.FormatInt(sinceTimestamp+1, 10))
-	queryParams.Add("_sort", "date_updated")
-	queryParams.Add("_limit", "1")
-	queryParams.Add("_offset", "0")
+	c.addTypeFilters(filters, itemTypeTag)
+	filters.Set("_sort", defaultSort)
+	return filters
+}
+
+// FetchModsPage fetches one page of the game's mods matching arbitrary mod.io filters, including
+// _sort. limit is clamped to mod.io's page size; _limit and _offset in filters are ignored.
+func (c *Client) FetchModsPage(ctx context.Context, filters url.Values, offset, limit int) (*ModioAPIResponse, error) {
+	path := c.gamePath("/mods")
+	queryParams := url.Values{}
+	for k, v := range filters { // Copy to avoid modifying caller's filters
+		queryParams[k] = v
+	}
+	queryParams.Set("_limit", strconv.Itoa(max(1, min(limit, apiPageSize))))
+	queryParams.Set("_offset", strconv.Itoa(max(0, offset)))
 
-	var modsResponse ModioAPIResponse
-	err := c.fetchGenericPaginatedData(ctx, path, queryParams, &modsResponse)
+	var apiResponse ModioAPIResponse
+	if err := c.fetchGenericPaginatedData(ctx, path, queryParams, &apiResponse); err != nil {
+		return nil, err
+	}
+	return &apiResponse, nil
+}
+
+// FetchLatestItems fetches a single page of the most recently updated items of a type.
+func (c *Client) FetchLatestItems(ctx context.Context, itemTypeTag string, limit int) ([]Mod, error) {
+	slog.Info("Fetching latest items from Mod.io", "type_tag", itemTypeTag, "limit", limit)
+	apiResponse, err := c.FetchModsPage(ctx, c.TypeFilters(itemTypeTag), 0, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch latest items for type %s: %w", itemTypeTag, err)
+	}
+	return apiResponse.Data, nil
+}
+
+func (c *Client) CheckForNewerMods(ctx context.Context, itemTypeTag string, sinceTimestamp int64) (bool, error) {
+	slog.Debug("Checking for newer mods via /mods endpoint", "type_tag", itemTypeTag, "since_timestamp", sinceTimestamp)
+	filters := c.TypeFilters(itemTypeTag)
+	filters.Set("date_updated-min", strconv.FormatInt(sinceTimestamp+1, 10))
+	filters.Set("_sort", "date_updated")
+
+	modsResponse, err := c.FetchModsPage(ctx, filters, 0, 1)
 	if err != nil {
 		return false, fmt.Errorf("failed to check for newer mods (type: %s, since: %d): %w", itemTypeTag, sinceTimestamp, err)
 	}
@@ -156,7 +315,7 @@ func (c *Client) CheckForNewerMods(ctx context.Context, itemTypeTag string, sinc
 }
 
 func (c *Client) FetchModEvents(ctx context.Context, sinceTimestamp int64, offset int, limit int) (*ModioEventsAPIResponse, error) {
-	path := fmt.Sprintf("/v1/games/%s/mods/events", c.gameID)
+	path := c.gamePath("/mods/events")
 	queryParams := url.Values{}
 	if sinceTimestamp > 0 {
 		queryParams.Add("date_added-min", strconv.FormatInt(sinceTimestamp+1, 10))
@@ -175,43 +334,182 @@ func (c *Client) FetchModEvents(ctx context.Context, sinceTimestamp int64, offse
 	return &eventsResponse, nil
 }
 
+// GetModDetails fetches a single mod. Transient failures are retried like paged fetches; a 404 matches
+// ErrNotFound.
 func (c *Client) GetModDetails(ctx context.Context, modID int) (*Mod, error) {
-	path := fmt.Sprintf("/v1/games/%s/mods/%d", c.gameID, modID)
-	actualParams := url.Values{} // Only api_key needed here
-	actualParams.Add("api_key", c.apiKey)
+	slog.Info("Fetching mod details from Mod.io", "mod_id", modID)
+	var mod Mod
+	if err := c.fetchGenericPaginatedData(ctx, c.gamePath(fmt.Sprintf("/mods/%d", modID)), url.Values{}, &mod); err != nil {
+		if IsNotFound(err) {
+			slog.Warn("Mod not found on Mod.io during GetModDetails", "mod_id", modID)
+		}
+		return nil, fmt.Errorf("mod details (id: %d): %w", modID, err)
+	}
+	return &mod, nil
+}
 
+// ModExists reports whether mod.io still serves a mod, without decoding it: a HEAD request, or a GET
+// with the body discarded where HEAD isn't allowed. A 404 is false; transient failures are retried like
+// paged fetches, and other failures return the typed errors.
+func (c *Client) ModExists(ctx context.Context, modID int) (bool, error) {
 	u := url.URL{
 		Scheme:   "https",
 		Host:     c.apiDomain,
-		Path:     path,
-		RawQuery: actualParams.Encode(),
+		Path:     c.gamePath(fmt.Sprintf("/mods/%d", modID)),
+		RawQuery: url.Values{"api_key": {*c.apiKey.Load()}}.Encode(),
 	}
 
-	slog.Info("Fetching mod details from Mod.io", "mod_id", modID)
+	var exists bool
+	err := c.withRetry(ctx, u.Path, func() error {
+		resp, err := c.probe(ctx, http.MethodHead, u)
+		if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+			resp, err = c.probe(ctx, http.MethodGet, u)
+		}
+		if err != nil {
+			return err
+		}
+		switch resp.StatusCode {
+		case http.StatusOK, http.StatusNotFound:
+			exists = resp.StatusCode == http.StatusOK
+			return nil
+		}
+		return newAPIError(u.Path, resp)
+	})
+	if err != nil {
+		return false, fmt.Errorf("mod existence check (id: %d): %w", modID, err)
+	}
+	return exists, nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+// probe sends a request for its status and headers only; the body is drained and closed.
+func (c *Client) probe(ctx context.Context, method string, u url.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request for mod details (id: %d): %w", modID, err)
+		return nil, fmt.Errorf("failed to create %s request: %w", method, err)
 	}
 	req.Header.Set("Accept", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make GET request for mod details (id: %d): %w", modID, err)
+		return nil, fmt.Errorf("failed to make %s request: %w", method, &transportError{err})
 	}
-	defer resp.Body.Close()
+	c.noteRateLimit(resp)
+	io.Copy(io.Discard, resp.Body) // Drain so the connection can be reused
+	resp.Body.Close()
+	return resp, nil
+}
+
+// GetModDetailsByIDs fetches mods by id through the paged /mods endpoint (id-in), one request per
+// apiPageSize ids. Ids mod.io doesn't return, e.g. deleted or hidden mods, are absent from the result.
+func (c *Client) GetModDetailsByIDs(ctx context.Context, modIDs []int) (map[int]*Mod, error) {
+	mods := make(map[int]*Mod, len(modIDs))
+	for start := 0; start < len(modIDs); start += apiPageSize {
+		chunk := modIDs[start:min(start+apiPageSize, len(modIDs))]
+		idStrs := make([]string, len(chunk))
+		for i, id := range chunk {
+			idStrs[i] = strconv.Itoa(id)
+		}
+		filters := url.Values{}
+		filters.Add("id-in", strings.Join(idStrs, ","))
 
-	if resp.StatusCode == http.StatusNotFound {
-		slog.Warn("Mod not found on Mod.io during GetModDetails", "mod_id", modID)
-		return nil, nil // Return nil, nil to indicate not found explicitly
+		slog.Info("Fetching mod details by ids from Mod.io", "count", len(chunk))
+		apiResponse, err := c.FetchModsPage(ctx, filters, 0, apiPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch mod details for %d ids: %w", len(chunk), err)
+		}
+		for i := range apiResponse.Data {
+			mods[apiResponse.Data[i].ID] = &apiResponse.Data[i]
+		}
+
+		if start+apiPageSize < len(modIDs) {
+			select {
+			case <-time.After(requestDelay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("mod.io API request for mod details (id: %d) failed with status %s", modID, resp.Status)
+	return mods, nil
+}
+
+// GetModfiles fetches a mod's modfile history, newest first, following pagination.
+func (c *Client) GetModfiles(ctx context.Context, modID int) ([]ModioModfile, error) {
+	path := c.gamePath(fmt.Sprintf("/mods/%d/files", modID))
+	var modfiles []ModioModfile
+	for page := 0; page < modfilePageSafeguard; page++ {
+		queryParams := url.Values{}
+		queryParams.Add("_sort", "-date_added")
+		queryParams.Add("_limit", strconv.Itoa(apiPageSize))
+		queryParams.Add("_offset", strconv.Itoa(page*apiPageSize))
+
+		var apiResponse ModioModfilesAPIResponse
+		if err := c.fetchGenericPaginatedData(ctx, path, queryParams, &apiResponse); err != nil {
+			return nil, fmt.Errorf("modfiles (mod id: %d, page %d): %w", modID, page+1, err) // Matches ErrNotFound on 404
+		}
+		modfiles = append(modfiles, apiResponse.Data...)
+		if len(apiResponse.Data) < apiPageSize || len(modfiles) >= apiResponse.ResultTotal {
+			break
+		}
+
+		select {
+		case <-time.After(requestDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
+	return modfiles, nil
+}
 
-	var mod Mod
-	if err := json.NewDecoder(resp.Body).Decode(&mod); err != nil {
-		return nil, fmt.Errorf("failed to decode JSON response for mod details (id: %d): %w", modID, err)
+// GetModDependencies fetches the ids of the mods modID depends on, following pagination.
+func (c *Client) GetModDependencies(ctx context.Context, modID int) ([]ModioDependency, error) {
+	path := c.gamePath(fmt.Sprintf("/mods/%d/dependencies", modID))
+	var dependencies []ModioDependency
+	for page := 0; page < modfilePageSafeguard; page++ {
+		queryParams := url.Values{}
+		queryParams.Add("_limit", strconv.Itoa(apiPageSize))
+		queryParams.Add("_offset", strconv.Itoa(page*apiPageSize))
+
+		var apiResponse ModioDependenciesAPIResponse
+		if err := c.fetchGenericPaginatedData(ctx, path, queryParams, &apiResponse); err != nil {
+			return nil, fmt.Errorf("dependencies (mod id: %d, page %d): %w", modID, page+1, err) // Matches ErrNotFound on 404
+		}
+		dependencies = append(dependencies, apiResponse.Data...)
+		if len(apiResponse.Data) < apiPageSize || len(dependencies) >= apiResponse.ResultTotal {
+			break
+		}
+
+		select {
+		case <-time.After(requestDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
-	return &mod, nil
-}
\ No newline at end of file
+	return dependencies, nil
+}
+
+// GetGameInfo fetches the configured game and keeps it for CachedGameInfo. A wrong MODIO_GAME_ID fails
+// with an error matching ErrNotFound.
+func (c *Client) GetGameInfo(ctx context.Context) (*ModioGame, error) {
+	var game ModioGame
+	if err := c.fetchGenericPaginatedData(ctx, c.gamePath(""), url.Values{}, &game); err != nil {
+		return nil, fmt.Errorf("failed to fetch game %s: %w", c.gameID, err)
+	}
+	c.gameInfo.Store(&game)
+	return &game, nil
+}
+
+// CachedGameInfo returns the game from the last successful GetGameInfo, or nil if there was none.
+func (c *Client) CachedGameInfo() *ModioGame {
+	return c.gameInfo.Load()
+}
+
+// GetGameTagOptions fetches the game's tag schema: the tag categories and the tags allowed in each.
+func (c *Client) GetGameTagOptions(ctx context.Context) ([]ModioGameTagOption, error) {
+	path := c.gamePath("/tags")
+	slog.Info("Fetching game tag options from Mod.io")
+	var apiResponse ModioGameTagOptionsAPIResponse
+	if err := c.fetchGenericPaginatedData(ctx, path, url.Values{}, &apiResponse); err != nil {
+		return nil, fmt.Errorf("failed to fetch game tag options: %w", err)
+	}
+	return apiResponse.Data, nil
+}
@@ -0,0 +1,194 @@
+package modio
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client whose requests go to handler over TLS, retrying up to attempts times
+// with a millisecond base delay.
+func newTestClient(t *testing.T, handler http.HandlerFunc, attempts int) *Client {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+	client := &Client{
+		httpClient: server.Client(),
+		gameID:     "629",
+		apiDomain:  strings.TrimPrefix(server.URL, "https://"),
+		apiVersion: "v1",
+		retry:      retryPolicy{attempts: attempts, baseDelay: time.Millisecond},
+	}
+	apiKey := "test-key"
+	client.apiKey.Store(&apiKey)
+	return client
+}
+
+func writeTestJSON(t *testing.T, w http.ResponseWriter, payload any) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		t.Errorf("encoding test response: %v", err)
+	}
+}
+
+// TestFetchAllItemsManyPages pages through a large catalogue; run it with -race to check the aggregation.
+func TestFetchAllItemsManyPages(t *testing.T) {
+	const total = 1050
+	var requests atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("_offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("_limit"))
+		page := ModioAPIResponse{ResultOffset: offset, ResultLimit: limit, ResultTotal: total}
+		for id := offset + 1; id <= min(offset+limit, total); id++ {
+			page.Data = append(page.Data, Mod{ID: id, Name: fmt.Sprintf("Mod %d", id)})
+		}
+		page.ResultCount = len(page.Data)
+		w.Header().Set("X-RateLimit-Limit", "1000") // Plenty left, so pages are fetched with the short delay
+		w.Header().Set("X-RateLimit-Remaining", "900")
+		writeTestJSON(t, w, page)
+	}, 1)
+
+	mods, err := client.FetchAllItems(context.Background(), "Map", 50)
+	if err != nil {
+		t.Fatalf("FetchAllItems: %v", err)
+	}
+	if len(mods) != total {
+		t.Fatalf("FetchAllItems: got %d mods, want %d", len(mods), total)
+	}
+	for i, mod := range mods {
+		if mod.ID != i+1 {
+			t.Fatalf("FetchAllItems: mod %d has id %d, want %d", i, mod.ID, i+1)
+		}
+	}
+	if got, want := requests.Load(), int32((total+apiPageSize-1)/apiPageSize); got != want {
+		t.Errorf("FetchAllItems made %d requests, want %d", got, want)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"503", context.Background(), &APIError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"wrapped 502", context.Background(), fmt.Errorf("page: %w", &APIError{StatusCode: http.StatusBadGateway}), true},
+		{"429", context.Background(), &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second}, true},
+		{"429 with long retry-after", context.Background(), &APIError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}, false},
+		{"401", context.Background(), &APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"404", context.Background(), &APIError{StatusCode: http.StatusNotFound}, false},
+		{"transport failure", context.Background(), &transportError{errors.New("connection reset")}, true},
+		{"decode failure", context.Background(), errors.New("invalid character"), false},
+		{"cancelled context", cancelled, &APIError{StatusCode: http.StatusServiceUnavailable}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("retryable(%v): got %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// failingFirst answers the first request with 503 and later ones with ok.
+func failingFirst(requests *atomic.Int32, ok http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		ok(w, r)
+	}
+}
+
+func TestGetModDetailsRetriesTransientFailures(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, failingFirst(&requests, func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(t, w, Mod{ID: 7, Name: "Plaza"})
+	}), 3)
+
+	mod, err := client.GetModDetails(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetModDetails: %v", err)
+	}
+	if mod.ID != 7 || mod.Name != "Plaza" {
+		t.Errorf("GetModDetails: got %+v, want mod 7 Plaza", mod)
+	}
+	if requests.Load() != 2 || client.Retries() != 1 {
+		t.Errorf("GetModDetails: %d requests and %d retries, want 2 and 1", requests.Load(), client.Retries())
+	}
+}
+
+func TestGetModDetailsNotFoundFailsFast(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}, 3)
+
+	if _, err := client.GetModDetails(context.Background(), 7); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetModDetails: got error %v, want ErrNotFound", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("GetModDetails: %d requests, want 1", requests.Load())
+	}
+}
+
+func TestModExistsRetriesTransientFailures(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, failingFirst(&requests, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("ModExists sent %s, want HEAD", r.Method)
+		}
+	}), 3)
+
+	exists, err := client.ModExists(context.Background(), 7)
+	if err != nil || !exists {
+		t.Errorf("ModExists: got %v, err %v, want true", exists, err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("ModExists: %d requests, want 2", requests.Load())
+	}
+}
+
+func TestRetryBackoffStopsOnCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var requests atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		cancel() // The caller gives up while the client backs off
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, 5)
+	client.retry.baseDelay = time.Hour
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetModDetails(ctx, 7)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("GetModDetails: got no error after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetModDetails kept backing off after its context was cancelled")
+	}
+	if requests.Load() != 1 {
+		t.Errorf("GetModDetails: %d requests, want 1", requests.Load())
+	}
+}
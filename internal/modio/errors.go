@@ -0,0 +1,118 @@
+package modio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Sentinel errors for the failure classes callers branch on. Errors returned by the client match them
+// with errors.Is (or the Is* helpers); use errors.As with *APIError for the status code, retry delay and
+// mod.io's own error details.
+var (
+	ErrNotFound     = errors.New("mod.io resource not found")
+	ErrRateLimited  = errors.New("mod.io rate limit exceeded")
+	ErrServerError  = errors.New("mod.io server error")
+	ErrUnauthorized = errors.New("mod.io rejected the API key")
+)
+
+const maxErrorBodySize = 16 << 10
+
+// APIError describes a non-2xx response from mod.io.
+type APIError struct {
+	Endpoint   string // Request path, without the query
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration // From the Retry-After header; zero when mod.io did not send one
+
+	// From mod.io's {"error": {...}} body; zero when the body was missing or not in that shape.
+	Code     int
+	ErrorRef int // mod.io's reference for the specific failure, stable across messages
+	Message  string
+}
+
+func newAPIError(endpoint string, resp *http.Response) *APIError {
+	apiErr := &APIError{
+		Endpoint:   endpoint,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	if resp.Body != nil {
+		var body struct {
+			Error struct {
+				Code     int    `json:"code"`
+				ErrorRef int    `json:"error_ref"`
+				Message  string `json:"message"`
+			} `json:"error"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, maxErrorBodySize)).Decode(&body) == nil {
+			apiErr.Code, apiErr.ErrorRef, apiErr.Message = body.Error.Code, body.Error.ErrorRef, body.Error.Message
+		}
+	}
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("mod.io API request to %s failed with status %s", e.Endpoint, e.Status)
+	if e.Message != "" {
+		msg += fmt.Sprintf(": %s (error_ref %d)", e.Message, e.ErrorRef)
+	}
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	return msg
+}
+
+// Is maps the status code onto the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrServerError:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// IsNotFound reports whether err is mod.io answering 404.
+func IsNotFound(err error) bool { return errors.Is(err, ErrNotFound) }
+
+// IsRateLimited reports whether err is mod.io answering 429; see RetryAfter for how long to wait.
+func IsRateLimited(err error) bool { return errors.Is(err, ErrRateLimited) }
+
+// IsUnauthorized reports whether err is mod.io rejecting the API key (401 or 403).
+func IsUnauthorized(err error) bool { return errors.Is(err, ErrUnauthorized) }
+
+// RetryAfter returns the delay mod.io asked for if err is a rate-limit error carrying one.
+func RetryAfter(err error) (time.Duration, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// parseRetryAfter accepts both forms of the header: delay seconds (what mod.io sends) and an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(at); delay > 0 {
+			return delay
+		}
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package modio
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/metrics"
+)
+
+const (
+	fastRequestDelay      = 100 * time.Millisecond // Between pages while more than half the quota is left
+	rateLimitLowRemaining = 5                      // Requests left at which the client waits out Retry-After
+)
+
+// RateLimitStatus is mod.io's rate limit as of the last response that reported it.
+type RateLimitStatus struct {
+	Limit      int           `json:"limit"`     // Requests allowed per window; 0 if not reported
+	Remaining  int           `json:"remaining"` // Requests left in the window; -1 if not reported
+	RetryAfter time.Duration `json:"retryAfter"`
+	ObservedAt time.Time     `json:"observedAt"`
+}
+
+// countingTransport counts every mod.io response by status code for the metrics endpoint.
+type countingTransport struct {
+	next http.RoundTripper // nil uses http.DefaultTransport
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	metrics.ObserveModioResponse(resp, err)
+	return resp, err
+}
+
+// RateLimitStatus returns the last rate limit mod.io reported, or nil before any response carried one.
+func (c *Client) RateLimitStatus() *RateLimitStatus {
+	return c.rateLimit.Load()
+}
+
+// noteRateLimit records the X-RateLimit-Limit, X-RateLimit-Remaining and Retry-After headers of resp. A
+// response without any of them leaves the last status in place.
+func (c *Client) noteRateLimit(resp *http.Response) {
+	limitStr := resp.Header.Get("X-RateLimit-Limit")
+	remainingStr := resp.Header.Get("X-RateLimit-Remaining")
+	retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+	if limitStr == "" && remainingStr == "" && retryAfter == 0 {
+		return
+	}
+	status := &RateLimitStatus{Remaining: -1, RetryAfter: retryAfter, ObservedAt: time.Now()}
+	if limit, err := strconv.Atoi(limitStr); err == nil {
+		status.Limit = limit
+	}
+	if remaining, err := strconv.Atoi(remainingStr); err == nil {
+		status.Remaining = remaining
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		status.Remaining = 0
+	}
+	c.rateLimit.Store(status)
+}
+
+// rateLimitPause returns how long to hold the next request: what is left of mod.io's Retry-After while
+// the quota is nearly or fully used up, otherwise zero.
+func (c *Client) rateLimitPause() time.Duration {
+	status := c.rateLimit.Load()
+	if status == nil || status.Remaining < 0 || status.Remaining > rateLimitLowRemaining || status.RetryAfter <= 0 {
+		return 0
+	}
+	return min(time.Until(status.ObservedAt.Add(status.RetryAfter)), maxRetryAfter)
+}
+
+// pageDelay returns the pause between paged requests: short while more than half the quota is left, the
+// remaining Retry-After when it is nearly used up, and requestDelay when mod.io hasn't said.
+func (c *Client) pageDelay() time.Duration {
+	if pause := c.rateLimitPause(); pause > 0 {
+		return pause
+	}
+	status := c.rateLimit.Load()
+	if status != nil && status.Limit > 0 && status.Remaining > status.Limit/2 {
+		return fastRequestDelay
+	}
+	return requestDelay
+}
@@ -0,0 +1,147 @@
+package modio
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const redactedAPIKey = "REDACTED"
+
+// recordedExchange is one request/response pair as stored in a golden file.
+type recordedExchange struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"` // api_key replaced with REDACTED
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// redactURL returns the URL with any api_key query value replaced, with query keys sorted so equal
+// requests produce equal strings.
+func redactURL(u *url.URL) string {
+	redacted := *u
+	query := redacted.Query()
+	if query.Has("api_key") {
+		query.Set("api_key", redactedAPIKey)
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// exchangeFile names the golden file for the n-th (0-based) occurrence of a request. Requests repeat
+// (e.g. event polling), so each occurrence gets its own file and replay serves them in order.
+func exchangeFile(dir, method, redactedURL string, n int) string {
+	sum := sha256.Sum256([]byte(method + " " + redactedURL))
+	return filepath.Join(dir, fmt.Sprintf("%s-%03d.json", hex.EncodeToString(sum[:8]), n))
+}
+
+// RecordingTransport passes requests through to Next and writes every exchange to Dir as a golden file
+// that ReplayTransport can serve back.
+type RecordingTransport struct {
+	Dir  string
+	Next http.RoundTripper
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func NewRecordingTransport(dir string, next http.RoundTripper) *RecordingTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RecordingTransport{Dir: dir, Next: next, counts: make(map[string]int)}
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to read response body from %s: %w", req.URL.Path, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	redacted := redactURL(req.URL)
+	t.mu.Lock()
+	n := t.counts[req.Method+" "+redacted]
+	t.counts[req.Method+" "+redacted] = n + 1
+	t.mu.Unlock()
+
+	data, err := json.MarshalIndent(recordedExchange{
+		Method:     req.Method,
+		URL:        redacted,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       string(body),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("recorder: failed to encode exchange for %s: %w", req.URL.Path, err)
+	}
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("recorder: failed to create %s: %w", t.Dir, err)
+	}
+	if err := os.WriteFile(exchangeFile(t.Dir, req.Method, redacted, n), data, 0o644); err != nil {
+		return nil, fmt.Errorf("recorder: failed to write exchange for %s: %w", req.URL.Path, err)
+	}
+	return resp, nil
+}
+
+// ReplayTransport serves exchanges recorded by RecordingTransport from Dir without touching the network.
+// Repeated requests get their recorded occurrences in order, then the last one again. Requests with no
+// recording fail, so missing golden files surface immediately.
+type ReplayTransport struct {
+	Dir string
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func NewReplayTransport(dir string) *ReplayTransport {
+	return &ReplayTransport{Dir: dir, counts: make(map[string]int)}
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	redacted := redactURL(req.URL)
+	key := req.Method + " " + redacted
+
+	t.mu.Lock()
+	n := t.counts[key]
+	data, err := os.ReadFile(exchangeFile(t.Dir, req.Method, redacted, n))
+	if err == nil {
+		t.counts[key] = n + 1
+	} else if n > 0 && os.IsNotExist(err) {
+		data, err = os.ReadFile(exchangeFile(t.Dir, req.Method, redacted, n-1))
+	}
+	t.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("replay: no recording for %s %s: %w", req.Method, redacted, err)
+	}
+
+	var exchange recordedExchange
+	if err := json.Unmarshal(data, &exchange); err != nil {
+		return nil, fmt.Errorf("replay: failed to decode recording for %s %s: %w", req.Method, redacted, err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.StatusCode, http.StatusText(exchange.StatusCode)),
+		StatusCode:    exchange.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        exchange.Header,
+		Body:          io.NopCloser(bytes.NewReader([]byte(exchange.Body))),
+		ContentLength: int64(len(exchange.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package modio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+const maxRetryAfter = time.Minute // A longer Retry-After fails the request instead of stalling it
+
+// retryPolicy bounds how withRetry retries transient failures.
+type retryPolicy struct {
+	attempts  int // Total tries, at least 1
+	baseDelay time.Duration
+}
+
+// transportError marks a failure to get a complete response at all: the request failed or the body was
+// cut off. Such failures are retried like 5xx responses.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// retryable reports whether err is worth another try: 429, 500, 502, 503 and 504 responses and transport
+// failures, unless ctx is done. Every other status, 401/403/404 included, fails at once.
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return apiErr.RetryAfter <= maxRetryAfter
+		}
+		return false
+	}
+	var transportErr *transportError
+	return errors.As(err, &transportErr)
+}
+
+// delay returns the wait before retry number attempt (1-based): mod.io's Retry-After if it sent one,
+// otherwise the base delay doubled per earlier retry, with up to half of it taken off at random so
+// instances that failed together don't retry together.
+func (p retryPolicy) delay(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter
+	}
+	backoff := p.baseDelay << min(attempt-1, 10)
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff - rand.N(backoff/2+1)
+}
+
+// isTruncatedBody reports whether a decode error means the connection dropped mid-body.
+func isTruncatedBody(err error) bool {
+	return errors.Is(err, io.ErrUnexpectedEOF)
+}
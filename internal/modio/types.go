@@ -3,30 +3,35 @@ package modio
 import "time"
 
 type ModioUser struct {
-	ID         int    `json:"id"`
+	ID         int    `json:"id,omitempty"` // Left out by the username-only submitter projection
 	Username   string `json:"username"`
-	ProfileURL string `json:"profile_url"`
+	ProfileURL string `json:"profile_url,omitempty"`
 }
 
 type ModioLogo struct {
-	Filename     string `json:"filename"`
-	Original     string `json:"original"`
-	Thumb320x180 string `json:"thumb_320x180"`
+	Filename      string `json:"filename"`
+	Original      string `json:"original"`
+	Thumb320x180  string `json:"thumb_320x180"`
+	Thumb640x360  string `json:"thumb_640x360,omitempty"`  // Omitted from list responses
+	Thumb1280x720 string `json:"thumb_1280x720,omitempty"` // Omitted from list responses
 }
 
 type ModioModfile struct {
-	ID       int    `json:"id"`
-	Filename string `json:"filename"`
-	Version  string `json:"version"`
-	Filesize int64  `json:"filesize"`
-	Download struct {
+	ID        int    `json:"id"`
+	Filename  string `json:"filename"`
+	Version   string `json:"version"`
+	Filesize  int64  `json:"filesize"`
+	DateAdded int64  `json:"date_added"`
+	Changelog string `json:"changelog,omitempty"` // Omitted from list responses
+	Download  struct {
 		BinaryURL   string `json:"binary_url"`
 		DateExpires int64  `json:"date_expires"`
 	} `json:"download"`
 }
 
 type ModioTag struct {
-	Name string `json:"name"`
+	Name       string `json:"name"`
+	Normalized string `json:"normalized,omitempty"` // Set only in responses, never by mod.io
 }
 
 type ModioStats struct {
@@ -35,12 +40,14 @@ type ModioStats struct {
 	RatingsPositive    int    `json:"ratings_positive"`
 	RatingsNegative    int    `json:"ratings_negative"`
 	RatingsDisplayText string `json:"ratings_display_text"`
+	CommentsTotal      int    `json:"comments_total"`
 }
 
 type ModioImage struct {
-	Filename     string `json:"filename"`
-	Original     string `json:"original"`
-	Thumb320x180 string `json:"thumb_320x180"`
+	Filename      string `json:"filename"`
+	Original      string `json:"original"`
+	Thumb320x180  string `json:"thumb_320x180"`
+	Thumb1280x720 string `json:"thumb_1280x720,omitempty"` // Omitted from list responses
 }
 
 type ModioMedia struct {
@@ -55,7 +62,7 @@ type Mod struct {
 	Summary     string       `json:"summary"`
 	Description string       `json:"description_plaintext"`
 	ProfileURL  string       `json:"profile_url"`
-	SubmittedBy ModioUser    `json:"submitted_by"`
+	SubmittedBy ModioUser    `json:"submitted_by,omitzero"`
 	DateAdded   int64        `json:"date_added"`
 	DateUpdated int64        `json:"date_updated"`
 	DateLive    int64        `json:"date_live"`
@@ -64,6 +71,27 @@ type Mod struct {
 	Tags        []ModioTag   `json:"tags"`
 	Stats       ModioStats   `json:"stats"`
 	Media       ModioMedia   `json:"media"`
+	// Dependencies reports whether the mod lists dependencies; fetch them with GetModDependencies.
+	Dependencies bool `json:"dependencies"`
+	// DescriptionTruncated is set on stored copies whose description was cut to the configured maximum.
+	// Never set by mod.io.
+	DescriptionTruncated bool `json:"description_truncated,omitempty"`
+}
+
+// ModioGame is the configured game as returned by /games/{id}, reduced to what startup checks and the
+// summary endpoint use.
+type ModioGame struct {
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	NameID     string         `json:"name_id"`
+	ProfileURL string         `json:"profile_url"`
+	Stats      ModioGameStats `json:"stats"`
+}
+
+type ModioGameStats struct {
+	ModsCountTotal       int `json:"mods_count_total"`
+	ModsDownloadsTotal   int `json:"mods_downloads_total"`
+	ModsSubscribersTotal int `json:"mods_subscribers_total"`
 }
 
 type ModioAPIResponse struct {
@@ -74,6 +102,44 @@ type ModioAPIResponse struct {
 	ResultTotal  int   `json:"result_total"`
 }
 
+type ModioModfilesAPIResponse struct {
+	Data         []ModioModfile `json:"data"`
+	ResultCount  int            `json:"result_count"`
+	ResultOffset int            `json:"result_offset"`
+	ResultLimit  int            `json:"result_limit"`
+	ResultTotal  int            `json:"result_total"`
+}
+
+type ModioDependency struct {
+	ModID     int   `json:"mod_id"`
+	DateAdded int64 `json:"date_added"`
+}
+
+type ModioDependenciesAPIResponse struct {
+	Data         []ModioDependency `json:"data"`
+	ResultCount  int               `json:"result_count"`
+	ResultOffset int               `json:"result_offset"`
+	ResultLimit  int               `json:"result_limit"`
+	ResultTotal  int               `json:"result_total"`
+}
+
+// ModioGameTagOption is one tag category of the game's tag schema.
+type ModioGameTagOption struct {
+	Name   string   `json:"name"`
+	Type   string   `json:"type"` // "dropdown" (pick one) or "checkboxes" (pick any)
+	Hidden bool     `json:"hidden"`
+	Locked bool     `json:"locked"`
+	Tags   []string `json:"tags"`
+}
+
+type ModioGameTagOptionsAPIResponse struct {
+	Data         []ModioGameTagOption `json:"data"`
+	ResultCount  int                  `json:"result_count"`
+	ResultOffset int                  `json:"result_offset"`
+	ResultLimit  int                  `json:"result_limit"`
+	ResultTotal  int                  `json:"result_total"`
+}
+
 type ModioEvent struct {
 	ID        int    `json:"id"`
 	ModID     int    `json:"mod_id"`
@@ -96,8 +162,3 @@ type CachedAPIResponse struct {
 	LastUpdated time.Time `json:"lastUpdated"`
 	TotalItems  int       `json:"totalItems"`
 }
-
-const (
-	MapTag       = "Map"
-	ScriptModTag = "Script"
-)
\ No newline at end of file
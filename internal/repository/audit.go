@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/redis/go-redis/v9"
+)
+
+// IntegrityAudit counts divergences between mod blobs and the type indexes found in one sample. It only
+// reports; a full sync repairs both kinds.
+type IntegrityAudit struct {
+	SampledIndexEntries     int `json:"sampledIndexEntries"`
+	IndexEntriesWithoutBlob int `json:"indexEntriesWithoutBlob"` // Type set members whose mod blob is gone
+	SampledBlobs            int `json:"sampledBlobs"`
+	BlobsMissingFromIndex   int `json:"blobsMissingFromIndex"` // Blobs absent from the type set their tags imply
+}
+
+// Add accumulates another sample's counts.
+func (a *IntegrityAudit) Add(other IntegrityAudit) {
+	a.SampledIndexEntries += other.SampledIndexEntries
+	a.IndexEntriesWithoutBlob += other.IndexEntriesWithoutBlob
+	a.SampledBlobs += other.SampledBlobs
+	a.BlobsMissingFromIndex += other.BlobsMissingFromIndex
+}
+
+// AuditTypeIndex checks up to sampleSize random members of a type's index set for a stored blob.
+func (r *ModRepository) AuditTypeIndex(ctx context.Context, modTypeTag string, sampleSize int) (IntegrityAudit, error) {
+	typeSetKey := r.keyPrefix + modTypeSetKeyPrefix + GetModTypeFromTag(modTypeTag)
+	ids, err := r.readRdb.SRandMemberN(ctx, typeSetKey, int64(sampleSize)).Result()
+	if err != nil {
+		return IntegrityAudit{}, fmt.Errorf("failed to sample %s: %w", typeSetKey, err)
+	}
+	_, missingIDs, err := r.mgetMods(ctx, r.readRdb, ids)
+	if err != nil {
+		return IntegrityAudit{}, fmt.Errorf("failed to load sampled mods of %s: %w", typeSetKey, err)
+	}
+	return IntegrityAudit{SampledIndexEntries: len(ids), IndexEntriesWithoutBlob: len(missingIDs)}, nil
+}
+
+// AuditBlobs checks about sampleSize mod blobs, continuing a keyspace scan at cursor, for membership in
+// the type sets their tags imply. It returns the cursor to resume from, 0 once the scan has wrapped, so
+// successive audits sweep every blob.
+func (r *ModRepository) AuditBlobs(ctx context.Context, cursor uint64, sampleSize int) (IntegrityAudit, uint64, error) {
+	var ids []string
+	for len(ids) < sampleSize {
+		keys, next, err := r.readRdb.Scan(ctx, cursor, r.keyPrefix+modKeyPrefix+"*", int64(sampleSize)).Result()
+		if err != nil {
+			return IntegrityAudit{}, cursor, fmt.Errorf("failed to scan mod blobs: %w", err)
+		}
+		for _, key := range keys {
+			ids = append(ids, strings.TrimPrefix(key, r.keyPrefix+modKeyPrefix))
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	mods, _, err := r.mgetMods(ctx, r.readRdb, ids)
+	if err != nil {
+		return IntegrityAudit{}, cursor, fmt.Errorf("failed to load sampled mod blobs: %w", err)
+	}
+	type membership struct {
+		mod *modio.Mod
+		cmd *redis.BoolCmd
+	}
+	var checks []membership
+	pipe := r.readRdb.Pipeline()
+	for _, mod := range mods {
+		for _, typeTag := range r.IndexedTypeTags() {
+			if hasTypeTag(mod, typeTag) || (typeTag == OtherTypeTag && r.ModTypeTag(mod) == "") {
+				typeSetKey := r.keyPrefix + modTypeSetKeyPrefix + GetModTypeFromTag(typeTag)
+				checks = append(checks, membership{mod: mod, cmd: pipe.SIsMember(ctx, typeSetKey, strconv.Itoa(mod.ID))})
+			}
+		}
+	}
+	if pipe.Len() > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return IntegrityAudit{}, cursor, fmt.Errorf("failed to check type set membership: %w", err)
+		}
+	}
+
+	audit := IntegrityAudit{SampledBlobs: len(mods)}
+	missing := make(map[int]bool)
+	for _, check := range checks {
+		if !check.cmd.Val() {
+			missing[check.mod.ID] = true
+		}
+	}
+	audit.BlobsMissingFromIndex = len(missing)
+	return audit, cursor, nil
+}
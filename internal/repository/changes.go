@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/config"
+	"github.com/ShawnEdgell/modio-api-go/internal/metrics"
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/redis/go-redis/v9"
+)
+
+// OtherTypeTag is the type mods without a type tag are indexed under with UNTYPED_MODS=other. No mod.io
+// tag carries it, and full syncs don't cover it.
+const OtherTypeTag = "Other"
+
+// ChangeSet collects mod writes for ApplyChanges. Only the last change recorded for a mod id is applied,
+// so callers can record events in order without coalescing them first. The zero value is ready to use.
+type ChangeSet struct {
+	changes map[int]modChange
+	order   []int
+
+	processedEventIDs []int
+	dedupWindow       time.Duration
+}
+
+type modChange struct {
+	mod     *modio.Mod // Nil for a delete
+	typeTag string
+}
+
+// Upsert stores mod under typeTag. An empty typeTag derives the type from the mod's tags, falling back to
+// the stored copy's type.
+func (c *ChangeSet) Upsert(mod *modio.Mod, typeTag string) {
+	c.record(mod.ID, modChange{mod: mod, typeTag: typeTag})
+}
+
+// Delete removes a mod from typeTag's indexes, and deletes it entirely unless it is still indexed under
+// another type it carries. An empty typeTag removes it from every type.
+func (c *ChangeSet) Delete(modID int, typeTag string) {
+	c.record(modID, modChange{typeTag: typeTag})
+}
+
+// Has reports whether a change is already recorded for the mod.
+func (c *ChangeSet) Has(modID int) bool {
+	_, ok := c.changes[modID]
+	return ok
+}
+
+// MarkEventsProcessed records event ids as processed when the change set is applied, in the same pipeline
+// as the mod writes: a failed write leaves its events unmarked, so they are replayed rather than lost.
+// The processed set expires after window if processing stops.
+func (c *ChangeSet) MarkEventsProcessed(window time.Duration, eventIDs ...int) {
+	c.processedEventIDs = append(c.processedEventIDs, eventIDs...)
+	c.dedupWindow = window
+}
+
+// Len returns the number of mods with a recorded change.
+func (c *ChangeSet) Len() int {
+	return len(c.order)
+}
+
+func (c *ChangeSet) record(modID int, change modChange) {
+	if c.changes == nil {
+		c.changes = make(map[int]modChange)
+	}
+	if _, ok := c.changes[modID]; !ok {
+		c.order = append(c.order, modID)
+	}
+	c.changes[modID] = change
+}
+
+// ChangeSummary reports what ApplyChanges wrote.
+type ChangeSummary struct {
+	UpsertedIDs []int
+	ChangedIDs  []int // Upserts that were new or carried a different date_updated than the stored copy
+	DeletedIDs  []int // Mods whose stored blob was deleted; index-only removals are not listed
+}
+
+// Add appends other's ids to s, for syncs that apply several change sets.
+func (s *ChangeSummary) Add(other ChangeSummary) {
+	s.UpsertedIDs = append(s.UpsertedIDs, other.UpsertedIDs...)
+	s.ChangedIDs = append(s.ChangedIDs, other.ChangedIDs...)
+	s.DeletedIDs = append(s.DeletedIDs, other.DeletedIDs...)
+}
+
+// ApplyChanges writes a change set, including its processed event ids, in a single pipeline. The stored
+// copy of every affected mod is loaded first so that index entries it no longer needs (tags, title, slug,
+// author, or whole types it left) are removed along with the write.
+func (r *ModRepository) ApplyChanges(ctx context.Context, changes *ChangeSet) (ChangeSummary, error) {
+	var summary ChangeSummary
+	if changes == nil || (changes.Len() == 0 && len(changes.processedEventIDs) == 0) {
+		return summary, nil
+	}
+
+	storedByID := make(map[int]*modio.Mod, changes.Len())
+	if changes.Len() > 0 {
+		modIDStrs := make([]string, len(changes.order))
+		for i, modID := range changes.order {
+			modIDStrs[i] = strconv.Itoa(modID)
+		}
+		storedMods, _, err := r.mgetMods(ctx, r.rdb, modIDStrs) // Always the primary: replica lag would orphan entries
+		if err != nil {
+			return summary, fmt.Errorf("failed to load stored mods for changes: %w", err)
+		}
+		for _, stored := range storedMods {
+			storedByID[stored.ID] = stored
+		}
+	}
+
+	pipe := r.rdb.Pipeline()
+	for _, modID := range changes.order {
+		change := changes.changes[modID]
+		stored := storedByID[modID]
+		if change.mod != nil {
+			typeTag, ok := r.upsertTypeTag(change.mod, stored, change.typeTag)
+			if !ok {
+				if r.untypedMods == config.UntypedModsDrop && r.addDeleteCommands(ctx, pipe, modID, stored, "") {
+					summary.DeletedIDs = append(summary.DeletedIDs, modID)
+				}
+				continue
+			}
+			if err := r.addUpsertCommands(ctx, pipe, stored, change.mod, typeTag); err != nil {
+				slog.Error("Failed to add save commands for mod", "mod_id", modID, "error", err)
+				continue
+			}
+			summary.UpsertedIDs = append(summary.UpsertedIDs, modID)
+			if stored == nil || stored.DateUpdated != change.mod.DateUpdated {
+				summary.ChangedIDs = append(summary.ChangedIDs, modID)
+			}
+			continue
+		}
+		if r.addDeleteCommands(ctx, pipe, modID, stored, change.typeTag) {
+			summary.DeletedIDs = append(summary.DeletedIDs, modID)
+		}
+	}
+	r.addMarkEventsProcessedCommands(ctx, pipe, changes.processedEventIDs, changes.dedupWindow)
+
+	if pipe.Len() > 0 {
+		slog.Debug("Executing change set pipeline", "mods", changes.Len(), "commands_in_pipe", pipe.Len())
+		metrics.RedisPipelineSize.Observe(float64(pipe.Len()))
+		if _, err := pipe.Exec(ctx); err != nil {
+			return ChangeSummary{}, fmt.Errorf("failed to execute Redis pipeline for changes: %w", err)
+		}
+	}
+	return summary, nil
+}
+
+// upsertTypeTag resolves the type a mod is stored under: typeTag if set, else the type its tags imply,
+// else its stored copy's type. Mods with none are handled by the UNTYPED_MODS strategy; false means the
+// mod must not be stored.
+func (r *ModRepository) upsertTypeTag(mod, stored *modio.Mod, typeTag string) (string, bool) {
+	if typeTag == "" {
+		typeTag = r.ModTypeTag(mod)
+	}
+	if typeTag == "" && stored != nil {
+		// If type cannot be determined from new tags we keep the old type when available.
+		typeTag = r.ModTypeTag(stored)
+	}
+	if typeTag != "" {
+		return typeTag, true
+	}
+	switch r.untypedMods {
+	case config.UntypedModsOther:
+		return OtherTypeTag, true
+	case config.UntypedModsDrop:
+		slog.Warn("Dropping mod without a category tag", "mod_id", mod.ID)
+	default:
+		slog.Debug("Skipping mod without a category tag", "mod_id", mod.ID)
+	}
+	return "", false
+}
+
+// addUpsertCommands queues storing mod under its resolved typeTag, see upsertTypeTag.
+func (r *ModRepository) addUpsertCommands(ctx context.Context, pipe redis.Pipeliner, stored, mod *modio.Mod, typeTag string) error {
+	if stored != nil {
+		// Mods whose tags moved them between types (e.g. Map tag removed, Script tag added) fully leave
+		// every type they no longer carry before being indexed under the new one.
+		for _, t := range r.typeTags {
+			if hasTypeTag(stored, t) && !hasTypeTag(mod, t) {
+				slog.Info("Mod left type, removing its index memberships", "mod_id", mod.ID, "old_type", t, "new_type", typeTag)
+				r.addRemoveModIndexCommands(ctx, pipe, stored, t)
+			}
+		}
+		if r.ModTypeTag(stored) == "" && typeTag != OtherTypeTag {
+			r.addRemoveModIndexCommands(ctx, pipe, stored, OtherTypeTag) // An untyped mod gained a type
+		}
+		r.addRemoveOrphanedIndexCommands(ctx, pipe, stored, mod, typeTag)
+	}
+	return r.addModCommands(ctx, pipe, mod, typeTag)
+}
+
+// addDeleteCommands queues a mod's removal and reports whether its stored blob is deleted.
+func (r *ModRepository) addDeleteCommands(ctx context.Context, pipe redis.Pipeliner, modID int, stored *modio.Mod, typeTag string) bool {
+	if stored == nil {
+		if typeTag == "" {
+			r.addDeleteModBlobCommands(ctx, pipe, modID)
+		} else {
+			r.addRemoveModIDCommands(ctx, pipe, strconv.Itoa(modID), typeTag)
+		}
+		return false
+	}
+
+	if typeTag != "" {
+		if r.indexedUnderOtherType(ctx, stored, typeTag) {
+			// The blob now belongs to another type, so only this type's indexes are stale.
+			r.addRemoveModIndexCommands(ctx, pipe, stored, typeTag)
+			return false
+		}
+		r.addRemoveModCommands(ctx, pipe, stored, typeTag)
+		return true
+	}
+
+	r.addRemoveModRecordCommands(ctx, pipe, stored)
+	for _, t := range r.typeTags {
+		if hasTypeTag(stored, t) {
+			r.addRemoveModIndexCommands(ctx, pipe, stored, t)
+		}
+	}
+	if r.ModTypeTag(stored) == "" {
+		r.addRemoveModIndexCommands(ctx, pipe, stored, OtherTypeTag)
+	}
+	return true
+}
+
+// indexedUnderOtherType reports whether a stored mod is still a member of another type's index, in which
+// case removing it from itemTypeTag must keep the blob.
+func (r *ModRepository) indexedUnderOtherType(ctx context.Context, mod *modio.Mod, itemTypeTag string) bool {
+	for _, t := range r.typeTags {
+		if t == itemTypeTag || !hasTypeTag(mod, t) {
+			continue
+		}
+		indexed, err := r.isModIndexedUnderType(ctx, mod.ID, t)
+		if err != nil {
+			slog.Warn("Failed to check type membership, assuming not indexed", "mod_id", mod.ID, "type", t, "error", err)
+			continue
+		}
+		if indexed {
+			return true
+		}
+	}
+	return false
+}
+
+// ModTypeTag returns the configured category a mod is indexed under, or "" if it carries none of them.
+// The first matching tag wins, mirroring mod.io's tag order.
+func (r *ModRepository) ModTypeTag(mod *modio.Mod) string {
+	for _, tag := range mod.Tags {
+		for _, typeTag := range r.typeTags {
+			if strings.EqualFold(tag.Name, typeTag) {
+				return typeTag
+			}
+		}
+	}
+	return ""
+}
+
+// IndexedTypeTags returns the types mods can be indexed under: the configured categories, plus
+// OtherTypeTag with UNTYPED_MODS=other.
+func (r *ModRepository) IndexedTypeTags() []string {
+	if r.untypedMods != config.UntypedModsOther {
+		return r.typeTags
+	}
+	return append(slices.Clip(r.typeTags), OtherTypeTag)
+}
+
+func hasTypeTag(mod *modio.Mod, typeTag string) bool {
+	for _, tag := range mod.Tags {
+		if strings.EqualFold(tag.Name, typeTag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/config"
+)
+
+func TestApplyChangesMigratesTypes(t *testing.T) {
+	tests := []struct {
+		name             string
+		oldName, newName string
+		oldTags, newTags []string
+		oldType, newType string
+	}{
+		{"map to script", "Plaza", "Plaza", []string{"Map", "Park"}, []string{"Script", "Park"}, "map", "script"},
+		{"script to map", "Grinds", "Grinds", []string{"Script", "Gameplay"}, []string{"Map", "Gameplay"}, "script", "map"},
+		{"type change with rename", "Old Plaza", "Plaza Tools", []string{"Map", "Park"}, []string{"Script", "Tools"}, "map", "script"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newTestRepository(t, nil)
+			oldMod := testMod(1, tt.oldName, tt.oldTags...)
+			newMod := testMod(1, tt.newName, tt.newTags...)
+
+			applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(oldMod, "") })
+			assertIndexed(t, repo, oldMod, tt.oldType, true)
+			applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(newMod, "") })
+
+			assertIndexed(t, repo, oldMod, tt.oldType, false)
+			assertIndexed(t, repo, newMod, tt.newType, true)
+			stored, err := repo.GetModByID(ctx, 1)
+			if err != nil || stored == nil {
+				t.Fatalf("GetModByID after migration: mod %v, err %v", stored, err)
+			}
+			if got := repo.ModTypeTag(stored); GetModTypeFromTag(got) != tt.newType {
+				t.Errorf("stored mod type: got %q, want %q", got, tt.newType)
+			}
+		})
+	}
+}
+
+func TestApplyChangesUntypedMods(t *testing.T) {
+	for _, strategy := range []string{config.UntypedModsSkip, config.UntypedModsDrop} {
+		t.Run(strategy, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newTestRepository(t, func(cfg *config.AppConfig) { cfg.UntypedMods = strategy })
+			applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(testMod(1, "Loose Rail", "Park"), "") })
+
+			if stored, err := repo.GetModByID(ctx, 1); err != nil || stored != nil {
+				t.Errorf("GetModByID: got mod %v, err %v, want no mod", stored, err)
+			}
+			if n, err := repo.rdb.Exists(ctx, repo.keyPrefix+modTypeSetKeyPrefix).Result(); err != nil || n != 0 {
+				t.Errorf("untyped mod created a blank type set: exists %d, err %v", n, err)
+			}
+		})
+	}
+
+	t.Run(config.UntypedModsOther, func(t *testing.T) {
+		ctx := context.Background()
+		repo := newTestRepository(t, func(cfg *config.AppConfig) { cfg.UntypedMods = config.UntypedModsOther })
+		untyped := testMod(1, "Loose Rail", "Park")
+		typed := testMod(2, "Downtown Plaza", "Map")
+		applyTestChanges(t, repo, func(c *ChangeSet) {
+			c.Upsert(untyped, "")
+			c.Upsert(typed, "")
+		})
+		assertIndexed(t, repo, untyped, "other", true)
+
+		mods, err := repo.GetModsByAuthorUpdatedSince(ctx, 42, untyped.DateUpdated-1)
+		if err != nil {
+			t.Fatalf("GetModsByAuthorUpdatedSince: %v", err)
+		}
+		var ids []int
+		for _, mod := range mods {
+			ids = append(ids, mod.ID)
+		}
+		if !slices.Equal(ids, []int{2, 1}) {
+			t.Errorf("GetModsByAuthorUpdatedSince ids: got %v, want [2 1]", ids)
+		}
+
+		audit, _, err := repo.AuditBlobs(ctx, 0, 10)
+		if err != nil {
+			t.Fatalf("AuditBlobs: %v", err)
+		}
+		if audit.SampledBlobs != 2 || audit.BlobsMissingFromIndex != 0 {
+			t.Errorf("AuditBlobs: got %+v, want 2 sampled blobs and none missing", audit)
+		}
+		repo.rdb.SRem(ctx, repo.keyPrefix+modTypeSetKeyPrefix+"other", untyped.ID)
+		if audit, _, err = repo.AuditBlobs(ctx, 0, 10); err != nil || audit.BlobsMissingFromIndex != 1 {
+			t.Errorf("AuditBlobs after removing the untyped mod from its type set: got %+v, err %v, want 1 missing", audit, err)
+		}
+	})
+}
+
+func TestApplyChangesMarksEventsProcessed(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, nil)
+	applyTestChanges(t, repo, func(c *ChangeSet) {
+		c.Upsert(testMod(1, "Plaza", "Map"), "")
+		c.MarkEventsProcessed(time.Hour, 101, 102)
+	})
+	applyTestChanges(t, repo, func(c *ChangeSet) { c.MarkEventsProcessed(time.Hour, 103) }) // Events without a write
+
+	seen, err := repo.FindProcessedEventIDs(ctx, []int{101, 102, 103, 104}, time.Hour)
+	if err != nil {
+		t.Fatalf("FindProcessedEventIDs: %v", err)
+	}
+	if want := map[int]bool{101: true, 102: true, 103: true}; !maps.Equal(seen, want) {
+		t.Errorf("processed event ids: got %v, want %v", seen, want)
+	}
+	if ttl := repo.rdb.TTL(ctx, repo.keyPrefix+processedEventIDsSortedSetKey).Val(); ttl <= 0 || ttl > time.Hour {
+		t.Errorf("processed event ids TTL: got %v, want up to 1h", ttl)
+	}
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newestFirstPage pages a date-scored sorted set newest first, ordering mods updated in the same second
+// by descending id. ZREVRANGEBYSCORE alone orders them by member bytes, putting "42" after "400". Scores
+// stay plain timestamps: a float64 can't hold a timestamp and a full mod id exactly, so ties are broken
+// here instead, by re-reading every member sharing the page's first or last date.
+func newestFirstPage(ctx context.Context, c redis.Cmdable, key, minScore string, limit, offset int) ([]string, error) {
+	page, err := c.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: minScore, Max: "+inf", Offset: int64(offset), Count: int64(limit)}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to page %s: %w", key, err)
+	}
+	if len(page) == 0 {
+		return []string{}, nil
+	}
+	newest := strconv.FormatFloat(page[0].Score, 'f', -1, 64)
+	oldest := strconv.FormatFloat(page[len(page)-1].Score, 'f', -1, 64)
+
+	// The dates at the page's edges may continue on the neighbouring pages, so the page is cut from the
+	// ordered span of every member between them.
+	pipe := c.Pipeline()
+	newerCmd := pipe.ZCount(ctx, key, "("+newest, "+inf")
+	spanCmd := pipe.ZRevRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{Min: oldest, Max: newest})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to read date ties in %s: %w", key, err)
+	}
+	span := spanCmd.Val()
+	sortDateTies(span)
+
+	start := min(max(offset-int(newerCmd.Val()), 0), len(span)) // Clamped in case a write landed between reads
+	ids := make([]string, 0, len(page))
+	for _, z := range span[start:min(start+len(page), len(span))] {
+		if id, ok := z.Member.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// dateOrderedIDs returns every member of a date-scored sorted set, newest first or, with oldestFirst,
+// oldest first. Mods updated in the same second come by descending id either way, as newestFirstPage
+// pages them.
+func dateOrderedIDs(ctx context.Context, c redis.Cmdable, key string, oldestFirst bool) ([]string, error) {
+	var entries []redis.Z
+	var err error
+	if oldestFirst {
+		entries, err = c.ZRangeWithScores(ctx, key, 0, -1).Result()
+	} else {
+		entries, err = c.ZRevRangeWithScores(ctx, key, 0, -1).Result()
+	}
+	if err != nil {
+		return nil, err
+	}
+	sortDateTies(entries)
+	ids := make([]string, 0, len(entries))
+	for _, z := range entries {
+		if id, ok := z.Member.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// newestFirstRank returns member's 0-based position in a date-scored sorted set as newestFirstPage orders
+// it, or redis.Nil if it isn't a member.
+func newestFirstRank(ctx context.Context, c redis.Cmdable, key, member string) (int64, error) {
+	score, err := c.ZScore(ctx, key, member).Result()
+	if err != nil {
+		return 0, err
+	}
+	date := strconv.FormatFloat(score, 'f', -1, 64)
+	pipe := c.Pipeline()
+	newerCmd := pipe.ZCount(ctx, key, "("+date, "+inf")
+	tiesCmd := pipe.ZRangeByScore(ctx, key, &redis.ZRangeBy{Min: date, Max: date})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	rank := newerCmd.Val()
+	for _, tie := range tiesCmd.Val() {
+		if newerMemberFirst(tie, member) {
+			rank++
+		}
+	}
+	return rank, nil
+}
+
+// sortDateTies orders each run of entries sharing a date by descending mod id, leaving the runs in place.
+// Scores stay plain timestamps (see newestFirstPage), so every reader of a date index breaks ties here.
+func sortDateTies(entries []redis.Z) {
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && entries[end].Score == entries[start].Score {
+			end++
+		}
+		run := entries[start:end]
+		sort.Slice(run, func(i, j int) bool { return newerMemberFirst(run[i].Member, run[j].Member) })
+		start = end
+	}
+}
+
+// GetDateRange returns the oldest and newest DateUpdated among a type's mods from the ends of its date
+// index, or zeros when the type has none.
+func (r *ModRepository) GetDateRange(ctx context.Context, modTypeTag string) (oldest, newest int64, err error) {
+	dateKey := r.keyPrefix + modDateUpdatedSortedSetKeyPrefix + GetModTypeFromTag(modTypeTag)
+	pipe := r.readRdb.Pipeline()
+	oldestCmd := pipe.ZRangeWithScores(ctx, dateKey, 0, 0)
+	newestCmd := pipe.ZRevRangeWithScores(ctx, dateKey, 0, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, fmt.Errorf("failed to read the ends of %s: %w", dateKey, err)
+	}
+	if len(oldestCmd.Val()) == 0 || len(newestCmd.Val()) == 0 {
+		return 0, 0, nil
+	}
+	return int64(oldestCmd.Val()[0].Score), int64(newestCmd.Val()[0].Score), nil
+}
+
+// newerMemberFirst orders sorted set members holding mod ids by descending numeric id.
+func newerMemberFirst(a, b interface{}) bool {
+	aStr, _ := a.(string)
+	bStr, _ := b.(string)
+	aID, aErr := strconv.Atoi(aStr)
+	bID, bErr := strconv.Atoi(bStr)
+	if aErr != nil || bErr != nil {
+		return aStr > bStr
+	}
+	return aID > bID
+}
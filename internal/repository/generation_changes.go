@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+)
+
+const (
+	generationChangesKeyPrefix = "modapi:changes:gen:" // JSON of the mods a generation changed and deleted
+	generationChangesKept      = 500                   // Newest generations whose change sets are kept
+)
+
+// ErrChangesUnavailable is returned when changes since a generation can no longer be reconstructed,
+// because that generation is older than the retained change sets or one of them is missing.
+var ErrChangesUnavailable = errors.New("change history since this generation is no longer available")
+
+type generationChanges struct {
+	Changed []int `json:"changed"`
+	Deleted []int `json:"deleted"`
+}
+
+// ChangesSince lists the mods changed and deleted after a generation, up to Generation.
+type ChangesSince struct {
+	SinceGeneration int64 `json:"sinceGeneration"`
+	Generation      int64 `json:"generation"`
+	Changed         []int `json:"changed"` // Added or updated mods, whatever their earlier history
+	Deleted         []int `json:"deleted"`
+}
+
+// RecordGenerationChanges stores what a generation changed and drops the change set that fell out of the
+// retained range. Generations with nothing changed are still recorded so gaps mean lost history.
+func (r *ModRepository) RecordGenerationChanges(ctx context.Context, generation int64, summary ChangeSummary) error {
+	payload, err := json.Marshal(generationChanges{Changed: nonNilIDs(summary.ChangedIDs), Deleted: nonNilIDs(summary.DeletedIDs)})
+	if err != nil {
+		return fmt.Errorf("failed to marshal generation changes: %w", err)
+	}
+	pipe := r.rdb.Pipeline()
+	pipe.Set(ctx, r.generationChangesKey(generation), payload, 0)
+	if expired := generation - generationChangesKept; expired > 0 {
+		pipe.Del(ctx, r.generationChangesKey(expired))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record changes for generation %d: %w", generation, err)
+	}
+	slog.Debug("Recorded generation changes", "generation", generation, "changed", len(summary.ChangedIDs), "deleted", len(summary.DeletedIDs))
+	return nil
+}
+
+// GetChangesSince merges the change sets of every generation after since. A mod deleted and later
+// re-added is only listed as changed, and one changed and later deleted only as deleted.
+func (r *ModRepository) GetChangesSince(ctx context.Context, since int64) (ChangesSince, error) {
+	current, err := r.GetSyncGeneration(ctx)
+	if err != nil {
+		return ChangesSince{}, fmt.Errorf("failed to get sync generation: %w", err)
+	}
+	result := ChangesSince{SinceGeneration: since, Generation: current, Changed: []int{}, Deleted: []int{}}
+	if since >= current {
+		return result, nil
+	}
+	if current-since > generationChangesKept {
+		return ChangesSince{}, ErrChangesUnavailable
+	}
+
+	keys := make([]string, 0, current-since)
+	for generation := since + 1; generation <= current; generation++ {
+		keys = append(keys, r.generationChangesKey(generation))
+	}
+	values, err := r.readRdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return ChangesSince{}, fmt.Errorf("failed to get generation changes: %w", err)
+	}
+
+	deleted := make(map[int]bool)
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			slog.Warn("Generation change set missing", "key", keys[i])
+			return ChangesSince{}, ErrChangesUnavailable
+		}
+		var changes generationChanges
+		if err := json.Unmarshal([]byte(raw), &changes); err != nil {
+			return ChangesSince{}, fmt.Errorf("failed to unmarshal %s: %w", keys[i], err)
+		}
+		for _, modID := range changes.Changed {
+			deleted[modID] = false
+		}
+		for _, modID := range changes.Deleted {
+			deleted[modID] = true
+		}
+	}
+	for modID, isDeleted := range deleted {
+		if isDeleted {
+			result.Deleted = append(result.Deleted, modID)
+		} else {
+			result.Changed = append(result.Changed, modID)
+		}
+	}
+	slices.Sort(result.Changed)
+	slices.Sort(result.Deleted)
+	return result, nil
+}
+
+func (r *ModRepository) generationChangesKey(generation int64) string {
+	return r.keyPrefix + generationChangesKeyPrefix + strconv.FormatInt(generation, 10)
+}
+
+func nonNilIDs(ids []int) []int {
+	if ids == nil {
+		return []int{}
+	}
+	return ids
+}
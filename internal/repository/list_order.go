@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// List orders accepted by GetListModIDs. A leading "-" means descending.
+const (
+	ListOrderNewest      = "" // Highest (newest) mod id first
+	ListOrderUpdated     = "date_updated"
+	ListOrderUpdatedDesc = "-date_updated"
+	ListOrderAdded       = "date_added"
+	ListOrderAddedDesc   = "-date_added"
+	ListOrderDownloads   = "downloads" // Most downloaded first
+	ListOrderComments    = "comments"  // Most commented first; only while INDEX_COMMENT_COUNTS is on
+	ListOrderName        = "name"      // By indexed (normalized) title
+)
+
+// ListOrders returns the explicit list orders, in the order they are documented. Comments is among them
+// only while comment counts are indexed.
+func (r *ModRepository) ListOrders() []string {
+	orders := []string{ListOrderUpdated, ListOrderUpdatedDesc, ListOrderAdded, ListOrderAddedDesc, ListOrderDownloads}
+	if r.indexCommentCounts {
+		orders = append(orders, ListOrderComments)
+	}
+	return append(orders, ListOrderName)
+}
+
+// ErrUnknownListOrder is returned by GetListModIDs for an order not in ListOrders.
+var ErrUnknownListOrder = errors.New("unknown list order")
+
+// ListFilter narrows the ids GetListModIDs returns. The zero value keeps every mod.
+type ListFilter struct {
+	HasMedia *bool    // Non-nil keeps only the mods with at least one screenshot, or only those without
+	Tags     []string // Keeps the mods carrying every one of these tags, or any of them with AnyTag
+	AnyTag   bool
+}
+
+// GetListModIDs returns the ids of a type's cached mods in the given order, which the type's sorted
+// indexes provide so pages sliced from the result stay stable, narrowed by filter. The media index decides
+// filter.HasMedia either way, so mods stored before it existed count as having no media until they are
+// next synced; likewise the date_added index. Tags are matched with SINTER (or SUNION for AnyTag) over the
+// type's tag sets, so an unknown tag simply matches nothing.
+func (r *ModRepository) GetListModIDs(ctx context.Context, modTypeTag string, order string, filter ListFilter) ([]string, error) {
+	modType := GetModTypeFromTag(modTypeTag)
+	var ids []string
+	var err error
+	switch order {
+	case ListOrderNewest:
+		ids, err = r.GetAllModIDsByType(ctx, modType)
+		sort.Slice(ids, func(i, j int) bool { return newerMemberFirst(ids[i], ids[j]) })
+	case ListOrderUpdated:
+		ids, err = dateOrderedIDs(ctx, r.readRdb, r.keyPrefix+modDateUpdatedSortedSetKeyPrefix+modType, true)
+	case ListOrderUpdatedDesc:
+		ids, err = dateOrderedIDs(ctx, r.readRdb, r.keyPrefix+modDateUpdatedSortedSetKeyPrefix+modType, false)
+	case ListOrderAdded:
+		ids, err = r.readRdb.ZRange(ctx, r.keyPrefix+modDateAddedSortedSetKeyPrefix+modType, 0, -1).Result()
+	case ListOrderAddedDesc:
+		ids, err = r.readRdb.ZRevRange(ctx, r.keyPrefix+modDateAddedSortedSetKeyPrefix+modType, 0, -1).Result()
+	case ListOrderDownloads:
+		ids, err = r.readRdb.ZRevRange(ctx, r.keyPrefix+modDownloadsSortedSetKeyPrefix+modType, 0, -1).Result()
+	case ListOrderComments:
+		if !r.indexCommentCounts {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownListOrder, order)
+		}
+		ids, err = r.readRdb.ZRevRange(ctx, r.keyPrefix+modCommentsSortedSetKeyPrefix+modType, 0, -1).Result()
+	case ListOrderName:
+		var members []string
+		members, err = r.readRdb.ZRange(ctx, r.keyPrefix+modTitleSortedSetKeyPrefix+modType, 0, -1).Result()
+		ids = make([]string, len(members))
+		for i, member := range members { // "normalizedtitle:id"; titles may contain colons
+			ids[i] = member[strings.LastIndexByte(member, ':')+1:]
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownListOrder, order)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s mod IDs in order %q: %w", modType, order, err)
+	}
+
+	if filter.HasMedia != nil {
+		mediaSetKey := r.keyPrefix + modsWithMediaSetKeyPrefix + modType
+		withMedia, err := r.readRdb.SMembersMap(ctx, mediaSetKey).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mod IDs from %s: %w", mediaSetKey, err)
+		}
+		ids = keepMembers(ids, withMedia, *filter.HasMedia)
+	}
+	if len(filter.Tags) > 0 {
+		tagged, err := r.modIDsWithTags(ctx, modType, filter.Tags, filter.AnyTag)
+		if err != nil {
+			return nil, err
+		}
+		ids = keepMembers(ids, tagged, true)
+	}
+	return ids, nil
+}
+
+// GetListPage returns the ids of one page of a list, offset ids in, and the number of ids across all
+// pages. Newest-updated lists narrowed only by tags that must all match are filtered and paged in Redis
+// by GetModIDsByTypeFiltered, so only the page is read; other lists are sliced from GetListModIDs.
+func (r *ModRepository) GetListPage(ctx context.Context, modTypeTag string, order string, filter ListFilter, offset, limit int) ([]string, int, error) {
+	if order == ListOrderUpdatedDesc && len(filter.Tags) > 0 && !filter.AnyTag && filter.HasMedia == nil {
+		ids, total, err := r.GetModIDsByTypeFiltered(ctx, modTypeTag, filter.Tags, 0, limit, offset)
+		return ids, int(total), err
+	}
+	ids, err := r.GetListModIDs(ctx, modTypeTag, order, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return ids[min(offset, len(ids)):min(offset+limit, len(ids))], len(ids), nil
+}
+
+// modIDsWithTags returns the ids of a type's mods carrying every tag, or any of them when anyTag is set.
+func (r *ModRepository) modIDsWithTags(ctx context.Context, modType string, tags []string, anyTag bool) (map[string]struct{}, error) {
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = fmt.Sprintf("%s%s:%s", r.keyPrefix+modTagSetKeyPrefix, r.normalize(tag), modType)
+	}
+	var members []string
+	var err error
+	if anyTag {
+		members, err = r.readRdb.SUnion(ctx, keys...).Result()
+	} else {
+		members, err = r.readRdb.SInter(ctx, keys...).Result()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine tag sets %v: %w", keys, err)
+	}
+	set := make(map[string]struct{}, len(members))
+	for _, member := range members {
+		set[member] = struct{}{}
+	}
+	return set, nil
+}
+
+// keepMembers filters ids in place, keeping those whose membership of set equals want.
+func keepMembers(ids []string, set map[string]struct{}, want bool) []string {
+	kept := ids[:0]
+	for _, id := range ids {
+		if _, ok := set[id]; ok == want {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
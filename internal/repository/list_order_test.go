@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestDateOrdersBreakTiesByDescendingID(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, nil)
+	older := testMod(7, "Old Ledge", "Map")
+	first, second := testMod(42, "Plaza", "Map"), testMod(400, "Stairs", "Map")
+	first.DateUpdated, second.DateUpdated = 1800000000, 1800000000 // "42" sorts after "400" by member bytes
+	applyTestChanges(t, repo, func(c *ChangeSet) {
+		c.Upsert(older, "")
+		c.Upsert(first, "")
+		c.Upsert(second, "")
+	})
+
+	for _, tt := range []struct {
+		order string
+		want  []string
+	}{
+		{ListOrderUpdatedDesc, []string{"400", "42", "7"}},
+		{ListOrderUpdated, []string{"7", "400", "42"}},
+	} {
+		ids, err := repo.GetListModIDs(ctx, "Map", tt.order, ListFilter{})
+		if err != nil {
+			t.Fatalf("GetListModIDs(%q): %v", tt.order, err)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("GetListModIDs(%q): got %v, want %v", tt.order, ids, tt.want)
+		}
+	}
+
+	for modID, want := range map[int]int64{400: 1, 42: 2, 7: 3} {
+		rank, err := repo.GetModRank(ctx, "Map", RankMetricUpdated, modID)
+		if err != nil || rank == nil {
+			t.Fatalf("GetModRank(%d): rank %v, err %v", modID, rank, err)
+		}
+		if rank.Rank != want {
+			t.Errorf("GetModRank(%d): got rank %d, want %d", modID, rank.Rank, want)
+		}
+	}
+}
+
+func TestGetListPageMatchesSlicedList(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, nil)
+	applyTestChanges(t, repo, func(c *ChangeSet) {
+		for id := 1; id <= 12; id++ {
+			tags := []string{"Map", "Park"}
+			if id%3 == 0 {
+				tags = append(tags, "Street")
+			}
+			mod := testMod(id, fmt.Sprintf("Spot %d", id), tags...)
+			mod.DateUpdated = 1800000000 + int64(id/4) // Runs of equal dates
+			c.Upsert(mod, "")
+		}
+	})
+
+	for _, filter := range []ListFilter{{Tags: []string{"park"}}, {Tags: []string{"Park", "Street"}}, {}} {
+		all, err := repo.GetListModIDs(ctx, "Map", ListOrderUpdatedDesc, filter)
+		if err != nil {
+			t.Fatalf("GetListModIDs(%v): %v", filter.Tags, err)
+		}
+		for offset := 0; offset <= len(all); offset += 5 {
+			page, total, err := repo.GetListPage(ctx, "Map", ListOrderUpdatedDesc, filter, offset, 5)
+			if err != nil {
+				t.Fatalf("GetListPage(%v, %d): %v", filter.Tags, offset, err)
+			}
+			want := all[offset:min(offset+5, len(all))]
+			if total != len(all) || !slices.Equal(page, want) {
+				t.Errorf("GetListPage(%v, %d): got %v of %d, want %v of %d", filter.Tags, offset, page, total, want, len(all))
+			}
+		}
+	}
+}
@@ -5,33 +5,65 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/ShawnEdgell/modio-api-go/internal/config"
 	"github.com/ShawnEdgell/modio-api-go/internal/modio"
 	"github.com/redis/go-redis/v9"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
-	// Exported for use by other packages if necessary (like scheduler for direct DEL on fallback)
-	ModKeyPrefix                           = "mod:" // Capitalized
-	modTypeSetKeyPrefix                    = "mods:type:"
-	modTitleSortedSetKeyPrefix             = "mod_titles:"
-	modDateUpdatedSortedSetKeyPrefix       = "mods_by_dateupdated:"
-	modTagSetKeyPrefix                     = "tag:"
-	systemLastOverallWriteTimestampKey     = "modapi:system:last_overall_write_ts"
+	modKeyPrefix                       = "mod:"
+	modTypeSetKeyPrefix                = "mods:type:"
+	modTitleSortedSetKeyPrefix         = "mod_titles:"
+	modDateUpdatedSortedSetKeyPrefix   = "mods_by_dateupdated:"
+	modDateAddedSortedSetKeyPrefix     = "mods_by_dateadded:"
+	modCommentsSortedSetKeyPrefix      = "mods_by_comments:"
+	modDownloadsSortedSetKeyPrefix     = "mods_by_downloads:"
+	modFullDescriptionKeyPrefix        = "mod_description:"      // Untruncated description of a mod stored truncated
+	modSlugHashKey                     = "mod_slugs"             // name_id -> mod id, shared by all types
+	recentlyDeletedSortedSetKey        = "mods:recently_deleted" // mod id scored by deletion time (unix seconds)
+	modAuthorSetKeyPrefix              = "mods:author:"          // submitter user id -> mod ids, shared by all types
+	syncCompletedChannel               = "modapi:events:sync_completed"
+	modTagSetKeyPrefix                 = "tag:"
+	modWordSetKeyPrefix                = "word:" // Per word and type: ids of mods whose name or summary has the word
+	systemLastOverallWriteTimestampKey = "modapi:system:last_overall_write_ts"
+	systemLastWriteTimestampKeyPrefix  = "modapi:system:last_write_ts:" // Per type, set when a full sync of it succeeds
 	schedulerLastSyncEventTimestampKey = "modapi:scheduler:last_sync_event_ts"
+	syncGenerationKey                  = "modapi:generation"
+	fullSyncTotalsHashKey              = "modapi:scheduler:full_sync_totals"
+	processedEventIDsSortedSetKey      = "modapi:scheduler:processed_event_ids" // event id scored by processing time (unix seconds)
+	tempSyncIDsKeyPrefix               = "modapi:tmp:sync_ids:"
+	tempFilterKeyPrefix                = "modapi:tmp:filter:"
+	tempSearchKeyPrefix                = "modapi:tmp:search:"
+	modsWithMediaSetKeyPrefix          = "mods:with_media:"         // Per type: ids of mods with at least one screenshot
+	tagOptionsCacheKey                 = "modapi:cache:tag_options" // JSON of mod.io's tag schema, with a TTL
+
+	tempKeyTTL    = 10 * time.Minute // Safety net in case a temp key's DEL never runs
+	sAddChunkSize = 1000
+	mgetChunkSize = 500 // Bounds the size of a single MGET reply
+
+	recentlyDeletedCap = 5000 // Newest deletions kept in recentlyDeletedSortedSetKey
 )
 
-// GetModTypeFromTag is now exported
+// DeletedMod is an entry of the recently deleted log.
+type DeletedMod struct {
+	ID        int   `json:"id"`
+	DeletedAt int64 `json:"deletedAt"` // Unix seconds
+}
+
+// GetModTypeFromTag returns the type name a category tag is indexed under, its lowercased form ("Map" is
+// "map", OtherTypeTag is "other").
 func GetModTypeFromTag(itemTypeTag string) string {
-	if strings.EqualFold(itemTypeTag, modio.MapTag) {
-		return "map"
-	} else if strings.EqualFold(itemTypeTag, modio.ScriptModTag) {
-		return "script"
-	}
-	slog.Warn("Unknown itemTypeTag for mod type conversion in repository", "tag", itemTypeTag)
 	return strings.ToLower(strings.TrimSpace(itemTypeTag))
 }
 
@@ -39,28 +71,112 @@ func normalizeStringForIndex(s string) string {
 	return strings.ToLower(strings.TrimSpace(s))
 }
 
+// foldUnicodeForIndex extends normalizeStringForIndex with compatibility folding (NFKC) and
+// diacritic stripping, so fullwidth or accented variants index to the same key.
+func foldUnicodeForIndex(s string) string {
+	folder := transform.Chain(norm.NFKD, runes.Remove(runes.In(unicode.Mn)), norm.NFKC)
+	folded, _, err := transform.String(folder, s)
+	if err != nil {
+		slog.Warn("Failed to fold unicode for index, falling back to basic normalization", "value", s, "error", err)
+		return normalizeStringForIndex(s)
+	}
+	return normalizeStringForIndex(folded)
+}
+
 type ModRepository struct {
-	rdb *redis.Client
+	rdb                *redis.Client
+	readRdb            *redis.Client // Read replica for API reads; same as rdb when none is configured
+	keyPrefix          string        // Namespaces every key and channel by game, see gameKeyPrefix
+	typeTags           []string      // The configured categories, see config.AppConfig.ModCategories
+	normalize          func(string) string
+	indexCommentCounts bool
+
+	allowPartialResults bool             // See StreamMods
+	tagIndexExclude     []*regexp.Regexp // Tags matching any of these get no tag set
+	maxDescriptionRunes int              // Longer descriptions are truncated in the blob; zero disables
+	untypedMods         string           // One of the config.UntypedMods* strategies
+	storeChangelogs     bool             // False drops modfile changelogs before storing
 }
 
-func NewModRepository(rdb *redis.Client) *ModRepository {
+// NewModRepository creates a repository that writes to rdb. Reads go to readRdb when it is non-nil,
+// otherwise to rdb as well.
+func NewModRepository(rdb *redis.Client, readRdb *redis.Client, cfg *config.AppConfig) *ModRepository {
 	if rdb == nil {
 		slog.Error("Redis client is nil in NewModRepository. Application may not function correctly.")
 	}
-	return &ModRepository{rdb: rdb}
+	if readRdb == nil {
+		readRdb = rdb
+	}
+	normalize := normalizeStringForIndex
+	if cfg.NormalizeUnicode {
+		normalize = foldUnicodeForIndex
+	}
+	return &ModRepository{
+		rdb:                 rdb,
+		readRdb:             readRdb,
+		keyPrefix:           gameKeyPrefix(cfg.ModioGameID),
+		typeTags:            cfg.ModCategories,
+		normalize:           normalize,
+		indexCommentCounts:  cfg.IndexCommentCounts,
+		allowPartialResults: cfg.AllowPartialResults,
+		tagIndexExclude:     cfg.TagIndexExclude,
+		maxDescriptionRunes: cfg.MaxStoredDescriptionLength,
+		untypedMods:         cfg.UntypedMods,
+		storeChangelogs:     cfg.StoreModfileChangelogs,
+	}
+}
+
+// gameKeyPrefix is prepended to every Redis key and channel name so several games can share a database.
+func gameKeyPrefix(gameID string) string {
+	return "game:" + gameID + ":"
 }
 
-// Client returns the underlying Redis client.
-// This allows other packages (like the scheduler) to create pipelines.
+// Primary returns a view of the repository whose reads also go to the primary. Read-modify-write
+// callers such as the scheduler need it, since a lagging replica would hand them outdated old data.
+func (r *ModRepository) Primary() *ModRepository {
+	primary := *r
+	primary.readRdb = r.rdb
+	return &primary
+}
+
+func (r *ModRepository) hasReadReplica() bool {
+	return r.readRdb != r.rdb
+}
+
+// NormalizeForIndex applies the same normalization used for index keys. Stored mods keep their
+// original strings, so this is only for matching, never for display.
+func (r *ModRepository) NormalizeForIndex(s string) string {
+	return r.normalize(s)
+}
+
+// Client returns the underlying Redis client, e.g. for health checks. Writes go through ApplyChanges.
 func (r *ModRepository) Client() *redis.Client {
 	return r.rdb
 }
 
-func (r *ModRepository) AddModCommandsToPipeline(ctx context.Context, pipe redis.Pipeliner, mod *modio.Mod, itemTypeTag string) error {
+// ReadClient returns the Redis client used for reads, which is the primary unless a replica is configured.
+func (r *ModRepository) ReadClient() *redis.Client {
+	return r.readRdb
+}
+
+func (r *ModRepository) addModCommands(ctx context.Context, pipe redis.Pipeliner, mod *modio.Mod, itemTypeTag string) error {
 	modType := GetModTypeFromTag(itemTypeTag) // Use exported version
 	modIDStr := strconv.Itoa(mod.ID)
+	if modType == "" {
+		return fmt.Errorf("refusing to index mod %d under a blank type", mod.ID)
+	}
 
-	modKey := ModKeyPrefix + modIDStr // Use exported version
+	if !r.storeChangelogs {
+		mod.Modfile.Changelog = ""
+	}
+	sanitizeModText(mod)
+	r.sortTags(mod)
+	if fullDescription, truncated := r.truncateDescription(mod); truncated {
+		pipe.Set(ctx, r.keyPrefix+modFullDescriptionKeyPrefix+modIDStr, fullDescription, 0)
+	} else {
+		pipe.Del(ctx, r.keyPrefix+modFullDescriptionKeyPrefix+modIDStr)
+	}
+	modKey := r.keyPrefix + modKeyPrefix + modIDStr
 	modJSON, err := json.Marshal(mod)
 	if err != nil {
 		slog.Error("Failed to marshal mod to JSON for pipeline", "mod_id", mod.ID, "error", err)
@@ -68,49 +184,175 @@ func (r *ModRepository) AddModCommandsToPipeline(ctx context.Context, pipe redis
 	}
 	pipe.Set(ctx, modKey, modJSON, 0)
 
-	pipe.SAdd(ctx, modTypeSetKeyPrefix+modType, modIDStr)
+	pipe.SAdd(ctx, r.keyPrefix+modTypeSetKeyPrefix+modType, modIDStr)
+	pipe.ZRem(ctx, r.keyPrefix+recentlyDeletedSortedSetKey, modIDStr) // A mod that came back is no longer deleted
+	if mod.NameID != "" {
+		pipe.HSet(ctx, r.keyPrefix+modSlugHashKey, normalizeStringForIndex(mod.NameID), modIDStr)
+	}
+	if mod.SubmittedBy.ID != 0 {
+		pipe.SAdd(ctx, r.keyPrefix+modAuthorSetKeyPrefix+strconv.Itoa(mod.SubmittedBy.ID), modIDStr)
+	}
 
-	normalizedTitle := normalizeStringForIndex(mod.Name)
+	normalizedTitle := r.normalize(mod.Name)
 	autocompleteMember := fmt.Sprintf("%s:%s", normalizedTitle, modIDStr)
-	pipe.ZAdd(ctx, modTitleSortedSetKeyPrefix+modType, redis.Z{Score: 0, Member: autocompleteMember})
+	pipe.ZAdd(ctx, r.keyPrefix+modTitleSortedSetKeyPrefix+modType, redis.Z{Score: 0, Member: autocompleteMember})
 
-	pipe.ZAdd(ctx, modDateUpdatedSortedSetKeyPrefix+modType, redis.Z{Score: float64(mod.DateUpdated), Member: modIDStr})
+	pipe.ZAdd(ctx, r.keyPrefix+modDateUpdatedSortedSetKeyPrefix+modType, redis.Z{Score: float64(mod.DateUpdated), Member: modIDStr})
+	pipe.ZAdd(ctx, r.keyPrefix+modDateAddedSortedSetKeyPrefix+modType, redis.Z{Score: float64(mod.DateAdded), Member: modIDStr})
+	if len(mod.Media.Images) > 0 {
+		pipe.SAdd(ctx, r.keyPrefix+modsWithMediaSetKeyPrefix+modType, modIDStr)
+	} else {
+		pipe.SRem(ctx, r.keyPrefix+modsWithMediaSetKeyPrefix+modType, modIDStr) // Its screenshots were removed on edit
+	}
+	pipe.ZAdd(ctx, r.keyPrefix+modDownloadsSortedSetKeyPrefix+modType, redis.Z{Score: float64(mod.Stats.DownloadsTotal), Member: modIDStr})
+	if r.indexCommentCounts {
+		pipe.ZAdd(ctx, r.keyPrefix+modCommentsSortedSetKeyPrefix+modType, redis.Z{Score: float64(mod.Stats.CommentsTotal), Member: modIDStr})
+	}
 
 	for _, tag := range mod.Tags {
-		normalizedTagName := normalizeStringForIndex(tag.Name)
-		tagSetKey := fmt.Sprintf("%s%s:%s", modTagSetKeyPrefix, normalizedTagName, modType)
+		normalizedTagName := r.normalize(tag.Name)
+		if !r.isTagIndexed(normalizedTagName) {
+			continue
+		}
+		tagSetKey := fmt.Sprintf("%s%s:%s", r.keyPrefix+modTagSetKeyPrefix, normalizedTagName, modType)
 		pipe.SAdd(ctx, tagSetKey, modIDStr)
 	}
+	r.addSearchWordCommands(ctx, pipe, mod, modType)
 	slog.Debug("Added commands to pipeline to save/update mod", "mod_id", mod.ID, "mod_name", mod.Name)
 	return nil
 }
 
-func (r *ModRepository) AddRemoveModCommandsFromPipeline(ctx context.Context, pipe redis.Pipeliner, mod *modio.Mod, itemTypeTag string) {
+// isTagIndexed reports whether a normalized tag gets a tag set. Removal paths ignore the exclusions, so
+// sets created before a tag was excluded are still cleaned up as mods change.
+func (r *ModRepository) isTagIndexed(normalizedTagName string) bool {
+	for _, pattern := range r.tagIndexExclude {
+		if pattern.MatchString(normalizedTagName) {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizeModText replaces invalid UTF-8 in a mod's text fields, which would otherwise be silently
+// rewritten by every encode and differ from the indexed forms.
+func sanitizeModText(mod *modio.Mod) {
+	for _, field := range []*string{
+		&mod.Name, &mod.NameID, &mod.Summary, &mod.Description, &mod.SubmittedBy.Username,
+		&mod.Modfile.Filename, &mod.Modfile.Version, &mod.Modfile.Changelog,
+	} {
+		*field = strings.ToValidUTF8(*field, "\uFFFD")
+	}
+	for i := range mod.Tags {
+		mod.Tags[i].Name = strings.ToValidUTF8(mod.Tags[i].Name, "\uFFFD")
+	}
+}
+
+// descriptionEllipsis marks a description truncated for storage.
+const descriptionEllipsis = "…"
+
+// truncateDescription cuts a description longer than the configured maximum and returns the original.
+func (r *ModRepository) truncateDescription(mod *modio.Mod) (string, bool) {
+	mod.DescriptionTruncated = false
+	if r.maxDescriptionRunes <= 0 || utf8.RuneCountInString(mod.Description) <= r.maxDescriptionRunes {
+		return "", false
+	}
+	full := mod.Description
+	runes := []rune(full)
+	mod.Description = strings.TrimRightFunc(string(runes[:r.maxDescriptionRunes]), unicode.IsSpace) + descriptionEllipsis
+	mod.DescriptionTruncated = true
+	return full, true
+}
+
+// RestoreFullDescription puts back the untruncated description of a mod stored with DescriptionTruncated.
+// The truncated one is kept if the full text isn't stored.
+func (r *ModRepository) RestoreFullDescription(ctx context.Context, mod *modio.Mod) error {
+	if !mod.DescriptionTruncated {
+		return nil
+	}
+	description, err := r.readRdb.Get(ctx, r.keyPrefix+modFullDescriptionKeyPrefix+strconv.Itoa(mod.ID)).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get full description of mod %d: %w", mod.ID, err)
+	}
+	mod.Description, mod.DescriptionTruncated = description, false
+	return nil
+}
+
+// sortTags orders a mod's tags canonically (by index form, then name) since mod.io's order isn't stable.
+// With sorted tags an unchanged mod marshals to byte-identical JSON on every sync.
+func (r *ModRepository) sortTags(mod *modio.Mod) {
+	sort.SliceStable(mod.Tags, func(i, j int) bool {
+		a, b := r.normalize(mod.Tags[i].Name), r.normalize(mod.Tags[j].Name)
+		if a != b {
+			return a < b
+		}
+		return mod.Tags[i].Name < mod.Tags[j].Name
+	})
+}
+
+func (r *ModRepository) addRemoveModCommands(ctx context.Context, pipe redis.Pipeliner, mod *modio.Mod, itemTypeTag string) {
+	r.addRemoveModRecordCommands(ctx, pipe, mod)
+	r.addRemoveModIndexCommands(ctx, pipe, mod, itemTypeTag)
+}
+
+// addRemoveModRecordCommands removes a mod's blob and its type-independent index entries (slug, author).
+func (r *ModRepository) addRemoveModRecordCommands(ctx context.Context, pipe redis.Pipeliner, mod *modio.Mod) {
+	r.addDeleteModBlobCommands(ctx, pipe, mod.ID)
+	if mod.NameID != "" {
+		pipe.HDel(ctx, r.keyPrefix+modSlugHashKey, normalizeStringForIndex(mod.NameID))
+	}
+	if mod.SubmittedBy.ID != 0 {
+		pipe.SRem(ctx, r.keyPrefix+modAuthorSetKeyPrefix+strconv.Itoa(mod.SubmittedBy.ID), strconv.Itoa(mod.ID))
+	}
+}
+
+// addDeleteModBlobCommands deletes a mod's stored blob and records the deletion in the
+// recently deleted log, which is capped to the newest recentlyDeletedCap entries.
+func (r *ModRepository) addDeleteModBlobCommands(ctx context.Context, pipe redis.Pipeliner, modID int) {
+	modIDStr := strconv.Itoa(modID)
+	pipe.Del(ctx, r.keyPrefix+modKeyPrefix+modIDStr, r.keyPrefix+modFullDescriptionKeyPrefix+modIDStr)
+	pipe.ZAdd(ctx, r.keyPrefix+recentlyDeletedSortedSetKey, redis.Z{Score: float64(time.Now().Unix()), Member: modIDStr})
+	pipe.ZRemRangeByRank(ctx, r.keyPrefix+recentlyDeletedSortedSetKey, 0, -(recentlyDeletedCap + 1))
+}
+
+// addRemoveModIndexCommands removes a mod from every index of the given type but keeps its
+// stored blob. Used when a mod migrates to another type rather than disappearing.
+func (r *ModRepository) addRemoveModIndexCommands(ctx context.Context, pipe redis.Pipeliner, mod *modio.Mod, itemTypeTag string) {
 	modType := GetModTypeFromTag(itemTypeTag) // Use exported version
 	modIDStr := strconv.Itoa(mod.ID)
 
-	pipe.Del(ctx, ModKeyPrefix+modIDStr) // Use exported version
-	pipe.SRem(ctx, modTypeSetKeyPrefix+modType, modIDStr)
+	pipe.SRem(ctx, r.keyPrefix+modTypeSetKeyPrefix+modType, modIDStr)
 
-	normalizedTitle := normalizeStringForIndex(mod.Name)
+	normalizedTitle := r.normalize(mod.Name)
 	autocompleteMember := fmt.Sprintf("%s:%s", normalizedTitle, modIDStr)
-	pipe.ZRem(ctx, modTitleSortedSetKeyPrefix+modType, autocompleteMember)
+	pipe.ZRem(ctx, r.keyPrefix+modTitleSortedSetKeyPrefix+modType, autocompleteMember)
 
-	pipe.ZRem(ctx, modDateUpdatedSortedSetKeyPrefix+modType, modIDStr)
+	pipe.ZRem(ctx, r.keyPrefix+modDateUpdatedSortedSetKeyPrefix+modType, modIDStr)
+	pipe.ZRem(ctx, r.keyPrefix+modDateAddedSortedSetKeyPrefix+modType, modIDStr)
+	pipe.ZRem(ctx, r.keyPrefix+modCommentsSortedSetKeyPrefix+modType, modIDStr) // Harmless when comment indexing is disabled
+	pipe.ZRem(ctx, r.keyPrefix+modDownloadsSortedSetKeyPrefix+modType, modIDStr)
+	pipe.SRem(ctx, r.keyPrefix+modsWithMediaSetKeyPrefix+modType, modIDStr)
 
 	for _, tag := range mod.Tags {
-		normalizedTagName := normalizeStringForIndex(tag.Name)
-		tagSetKey := fmt.Sprintf("%s%s:%s", modTagSetKeyPrefix, normalizedTagName, modType)
+		normalizedTagName := r.normalize(tag.Name)
+		tagSetKey := fmt.Sprintf("%s%s:%s", r.keyPrefix+modTagSetKeyPrefix, normalizedTagName, modType)
 		pipe.SRem(ctx, tagSetKey, modIDStr)
 	}
-	slog.Debug("Added commands to pipeline for removing mod", "mod_id", mod.ID)
+	r.addRemoveSearchWordCommands(ctx, pipe, mod, nil, modType)
+	slog.Debug("Added commands to pipeline for removing mod from indexes", "mod_id", mod.ID, "type", modType)
 }
 
 func (r *ModRepository) GetModByID(ctx context.Context, modID int) (*modio.Mod, error) {
-	modKey := ModKeyPrefix + strconv.Itoa(modID) // Use exported version
+	modKey := r.keyPrefix + modKeyPrefix + strconv.Itoa(modID)
 	slog.Debug("Fetching mod by ID from Redis", "key", modKey)
 
-	modJSON, err := r.rdb.Get(ctx, modKey).Result()
+	modJSON, err := r.readRdb.Get(ctx, modKey).Result()
+	if err == redis.Nil && r.hasReadReplica() {
+		// The mod may have just been written and not replicated yet.
+		modJSON, err = r.rdb.Get(ctx, modKey).Result()
+	}
 	if err == redis.Nil {
 		slog.Debug("Mod not found in Redis", "mod_id", modID, "key", modKey)
 		return nil, nil
@@ -129,46 +371,153 @@ func (r *ModRepository) GetModByID(ctx context.Context, modID int) (*modio.Mod,
 }
 
 func (r *ModRepository) GetModsByIDs(ctx context.Context, modIDs []string) ([]*modio.Mod, error) {
+	mods, _, err := r.getModsByIDs(ctx, modIDs, false)
+	return mods, err
+}
+
+// getModsByIDs is GetModsByIDs that also returns how many mods were dropped, see mgetModsTolerant.
+func (r *ModRepository) getModsByIDs(ctx context.Context, modIDs []string, tolerateChunkErrors bool) ([]*modio.Mod, int, error) {
 	if len(modIDs) == 0 {
-		return []*modio.Mod{}, nil
-	}
-	keys := make([]string, len(modIDs))
-	for i, idStr := range modIDs {
-		keys[i] = ModKeyPrefix + idStr // Use exported version
+		return []*modio.Mod{}, 0, nil
 	}
 
-	slog.Debug("Fetching multiple mods by IDs from Redis", "count", len(keys))
-	results, err := r.rdb.MGet(ctx, keys...).Result()
+	slog.Debug("Fetching multiple mods by IDs from Redis", "count", len(modIDs))
+	mods, missingIDs, dropped, err := r.mgetModsTolerant(ctx, r.readRdb, modIDs, tolerateChunkErrors)
 	if err != nil {
-		slog.Error("Failed to MGET mods from Redis", "error", err)
-		return nil, err
+		return nil, 0, err
+	}
+	if len(missingIDs) > 0 && r.hasReadReplica() {
+		// Ids from a fresh index entry can point at blobs the replica has not received yet.
+		slog.Debug("Retrying mods missing on the read replica against the primary", "count", len(missingIDs))
+		primaryMods, _, err := r.mgetMods(ctx, r.rdb, missingIDs)
+		if err != nil {
+			slog.Warn("Failed to fetch mods missing on the read replica from the primary", "count", len(missingIDs), "error", err)
+		} else {
+			mods = append(mods, primaryMods...)
+		}
 	}
+	return mods, dropped, nil
+}
 
-	mods := make([]*modio.Mod, 0, len(results))
-	for i, res := range results {
-		if res == nil {
-			slog.Debug("Mod ID not found during MGET", "id_queried", modIDs[i])
-			continue
+// mgetMods fetches and decodes mod blobs in MGET chunks, returning the ids that had no blob separately.
+// Blobs that fail to decode are skipped. Any failed chunk fails the whole call.
+func (r *ModRepository) mgetMods(ctx context.Context, client *redis.Client, modIDs []string) ([]*modio.Mod, []string, error) {
+	mods, missingIDs, _, err := r.mgetModsTolerant(ctx, client, modIDs, false)
+	return mods, missingIDs, err
+}
+
+// mgetModsTolerant is mgetMods that also counts the mods it had to drop: blobs that failed to decode
+// and, when tolerateChunkErrors is set, every id of a chunk whose MGET failed instead of failing the call.
+func (r *ModRepository) mgetModsTolerant(ctx context.Context, client *redis.Client, modIDs []string, tolerateChunkErrors bool) ([]*modio.Mod, []string, int, error) {
+	mods := make([]*modio.Mod, 0, len(modIDs))
+	var missingIDs []string
+	dropped := 0
+	for start := 0; start < len(modIDs); start += mgetChunkSize {
+		chunkIDs := modIDs[start:min(start+mgetChunkSize, len(modIDs))]
+		keys := make([]string, len(chunkIDs))
+		for i, idStr := range chunkIDs {
+			keys[i] = r.keyPrefix + modKeyPrefix + idStr
 		}
-		modJSON, ok := res.(string)
-		if !ok {
-			slog.Error("Unexpected type from MGET result for mod ID", "id_queried", modIDs[i], "type", fmt.Sprintf("%T", res))
-			continue
+
+		results, err := client.MGet(ctx, keys...).Result()
+		if err != nil {
+			slog.Error("Failed to MGET mods from Redis", "chunk_start", start, "chunk_size", len(keys), "error", err)
+			if tolerateChunkErrors && ctx.Err() == nil {
+				dropped += len(chunkIDs)
+				continue
+			}
+			return nil, nil, 0, err
 		}
-		var mod modio.Mod
-		if err := json.Unmarshal([]byte(modJSON), &mod); err != nil {
-			slog.Error("Failed to unmarshal mod JSON from MGET result", "id_queried", modIDs[i], "error", err)
+
+		for i, res := range results {
+			if res == nil {
+				slog.Debug("Mod ID not found during MGET", "id_queried", chunkIDs[i])
+				missingIDs = append(missingIDs, chunkIDs[i])
+				continue
+			}
+			var modJSON []byte
+			switch v := res.(type) {
+			case string:
+				modJSON = []byte(v)
+			case []byte:
+				modJSON = v
+			default:
+				slog.Error("Unexpected type from MGET result for mod ID", "id_queried", chunkIDs[i], "type", fmt.Sprintf("%T", res))
+				dropped++
+				continue
+			}
+			var mod modio.Mod
+			if err := json.Unmarshal(modJSON, &mod); err != nil {
+				slog.Error("Failed to unmarshal mod JSON from MGET result", "id_queried", chunkIDs[i], "error", err)
+				dropped++
+				continue
+			}
+			mods = append(mods, &mod)
+		}
+	}
+	if len(missingIDs) > 0 {
+		var collided int
+		missingIDs, collided = r.dropKeyCollisions(ctx, client, missingIDs)
+		dropped += collided
+	}
+	return mods, missingIDs, dropped, nil
+}
+
+// dropKeyCollisions removes from missingIDs the ids whose mod key exists but holds something other than a
+// string blob, which MGET reports as nil just like an absent key. Such a key means a different key family
+// overlapped the blob keys; each one is logged and counted. If the check itself fails missingIDs is
+// returned unchanged.
+func (r *ModRepository) dropKeyCollisions(ctx context.Context, client *redis.Client, missingIDs []string) ([]string, int) {
+	pipe := client.Pipeline()
+	typeCmds := make([]*redis.StatusCmd, len(missingIDs))
+	for i, idStr := range missingIDs {
+		typeCmds[i] = pipe.Type(ctx, r.keyPrefix+modKeyPrefix+idStr)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Warn("Failed to check the key type of mods missing from MGET", "count", len(missingIDs), "error", err)
+		return missingIDs, 0
+	}
+	stillMissing := make([]string, 0, len(missingIDs))
+	collided := 0
+	for i, cmd := range typeCmds {
+		if keyType := cmd.Val(); keyType != "none" && keyType != "string" {
+			slog.Error("Mod key holds a non-string value, another key family may overlap the mod blob keys", "key", r.keyPrefix+modKeyPrefix+missingIDs[i], "redis_type", keyType)
+			collided++
 			continue
 		}
-		mods = append(mods, &mod)
+		stillMissing = append(stillMissing, missingIDs[i])
 	}
-	return mods, nil
+	return stillMissing, collided
+}
+
+// ResolveSlugs maps mod.io name_id slugs to mod ids using the slug index. Unknown slugs are absent
+// from the returned map, which is keyed by the slug exactly as given.
+func (r *ModRepository) ResolveSlugs(ctx context.Context, slugs []string) (map[string]string, error) {
+	resolved := make(map[string]string, len(slugs))
+	if len(slugs) == 0 {
+		return resolved, nil
+	}
+	fields := make([]string, len(slugs))
+	for i, slug := range slugs {
+		fields[i] = normalizeStringForIndex(slug)
+	}
+	results, err := r.readRdb.HMGet(ctx, r.keyPrefix+modSlugHashKey, fields...).Result()
+	if err != nil {
+		slog.Error("Failed to resolve slugs from Redis", "count", len(slugs), "error", err)
+		return nil, err
+	}
+	for i, res := range results {
+		if modIDStr, ok := res.(string); ok {
+			resolved[slugs[i]] = modIDStr
+		}
+	}
+	return resolved, nil
 }
 
 func (r *ModRepository) GetAllModIDsByType(ctx context.Context, modType string) ([]string, error) {
-	typeSetKey := modTypeSetKeyPrefix + normalizeStringForIndex(modType)
+	typeSetKey := r.keyPrefix + modTypeSetKeyPrefix + normalizeStringForIndex(modType)
 	slog.Debug("Fetching all mod IDs by type from Redis Set", "key", typeSetKey)
-	ids, err := r.rdb.SMembers(ctx, typeSetKey).Result()
+	ids, err := r.readRdb.SMembers(ctx, typeSetKey).Result()
 	if err != nil {
 		slog.Error("Failed to get mod IDs from type set in Redis", "key", typeSetKey, "error", err)
 		return nil, err
@@ -176,34 +525,203 @@ func (r *ModRepository) GetAllModIDsByType(ctx context.Context, modType string)
 	return ids, nil
 }
 
-func (r *ModRepository) GetModsByType(ctx context.Context, modTypeTag string) ([]modio.Mod, time.Time, error) {
-	modType := GetModTypeFromTag(modTypeTag) // Use exported version
-	ids, err := r.GetAllModIDsByType(ctx, modType)
-	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("failed to get mod IDs for type %s: %w", modType, err)
+// isModIndexedUnderType reports whether the mod is a member of the given type's index set.
+func (r *ModRepository) isModIndexedUnderType(ctx context.Context, modID int, itemTypeTag string) (bool, error) {
+	return r.rdb.SIsMember(ctx, r.keyPrefix+modTypeSetKeyPrefix+GetModTypeFromTag(itemTypeTag), strconv.Itoa(modID)).Result()
+}
+
+// FindStaleModIDs returns the ids indexed under the type that are not in currentIDs. The current ids are
+// staged into a temporary set so Redis computes the difference with SDIFF instead of us round-tripping the
+// whole index.
+func (r *ModRepository) FindStaleModIDs(ctx context.Context, itemTypeTag string, currentIDs []string) ([]string, error) {
+	modType := GetModTypeFromTag(itemTypeTag)
+	typeSetKey := r.keyPrefix + modTypeSetKeyPrefix + modType
+	if len(currentIDs) == 0 {
+		return r.rdb.SMembers(ctx, typeSetKey).Result()
+	}
+
+	tempKey := fmt.Sprintf("%s%s:%d", r.keyPrefix+tempSyncIDsKeyPrefix, modType, time.Now().UnixNano())
+	defer func() {
+		if err := r.rdb.Del(context.WithoutCancel(ctx), tempKey).Err(); err != nil {
+			slog.Warn("Failed to delete temporary sync id set", "key", tempKey, "error", err)
+		}
+	}()
+
+	pipe := r.rdb.Pipeline()
+	for start := 0; start < len(currentIDs); start += sAddChunkSize {
+		end := min(start+sAddChunkSize, len(currentIDs))
+		members := make([]interface{}, 0, end-start)
+		for _, id := range currentIDs[start:end] {
+			members = append(members, id)
+		}
+		pipe.SAdd(ctx, tempKey, members...)
+	}
+	pipe.Expire(ctx, tempKey, tempKeyTTL)
+	staleCmd := pipe.SDiff(ctx, typeSetKey, tempKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to diff %s index against current ids: %w", modType, err)
+	}
+	return staleCmd.Val(), nil
+}
+
+// GetModIDsByTypeFiltered returns a page of the ids of a type's mods carrying every tag in tags and
+// updated after updatedSince (when positive), newest update first with ties by descending id, along with
+// the total number of matches.
+// The filtering runs in Redis: one ZINTERSTORE of the date-updated index with the tag sets (weighted 0,
+// so scores stay dates) into a temp key, which is then counted and paged. It writes, so it always runs on
+// the primary.
+func (r *ModRepository) GetModIDsByTypeFiltered(ctx context.Context, modTypeTag string, tags []string, updatedSince int64, limit, offset int) ([]string, int64, error) {
+	modType := GetModTypeFromTag(modTypeTag)
+	dateKey := r.keyPrefix + modDateUpdatedSortedSetKeyPrefix + modType
+	minScore := "-inf"
+	if updatedSince > 0 {
+		minScore = "(" + strconv.FormatInt(updatedSince, 10)
 	}
 
-	modPointers, err := r.GetModsByIDs(ctx, ids)
+	if len(tags) == 0 {
+		total, err := r.readRdb.ZCount(ctx, dateKey, minScore, "+inf").Result()
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to count %s: %w", dateKey, err)
+		}
+		ids, err := newestFirstPage(ctx, r.readRdb, dateKey, minScore, limit, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		return ids, total, nil
+	}
+
+	keys := []string{dateKey}
+	weights := []float64{1}
+	for _, tag := range tags {
+		keys = append(keys, fmt.Sprintf("%s%s:%s", r.keyPrefix+modTagSetKeyPrefix, r.normalize(tag), modType))
+		weights = append(weights, 0)
+	}
+	tempKey := fmt.Sprintf("%s%s:%d", r.keyPrefix+tempFilterKeyPrefix, modType, time.Now().UnixNano())
+	defer func() {
+		if err := r.rdb.Del(context.WithoutCancel(ctx), tempKey).Err(); err != nil {
+			slog.Warn("Failed to delete temp filter key, it expires on its own", "key", tempKey, "error", err)
+		}
+	}()
+
+	pipe := r.rdb.Pipeline()
+	pipe.ZInterStore(ctx, tempKey, &redis.ZStore{Keys: keys, Weights: weights})
+	pipe.Expire(ctx, tempKey, tempKeyTTL)
+	totalCmd := pipe.ZCount(ctx, tempKey, minScore, "+inf")
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, fmt.Errorf("failed to filter %s mods by tags %v: %w", modType, tags, err)
+	}
+	ids, err := newestFirstPage(ctx, r.rdb, tempKey, minScore, limit, offset)
 	if err != nil {
-		return nil, time.Time{}, fmt.Errorf("failed to get mods by IDs for type %s: %w", modType, err)
+		return nil, 0, fmt.Errorf("failed to filter %s mods by tags %v: %w", modType, tags, err)
 	}
+	return ids, totalCmd.Val(), nil
+}
 
-	mods := make([]modio.Mod, 0, len(modPointers))
-	for _, modPtr := range modPointers {
-		if modPtr != nil {
-			mods = append(mods, *modPtr)
+// CountModsByFilter counts a type's mods carrying every tag in tags with SINTERCARD (Redis 7+), without
+// loading them. Older servers fall back to SINTER.
+func (r *ModRepository) CountModsByFilter(ctx context.Context, modTypeTag string, tags []string) (int64, error) {
+	modType := GetModTypeFromTag(modTypeTag)
+	keys := []string{r.keyPrefix + modTypeSetKeyPrefix + modType}
+	for _, tag := range tags {
+		normalizedTagName := r.normalize(tag)
+		if !r.isTagIndexed(normalizedTagName) {
+			return 0, nil // Excluded tags have no set, so nothing matches
 		}
+		keys = append(keys, fmt.Sprintf("%s%s:%s", r.keyPrefix+modTagSetKeyPrefix, normalizedTagName, modType))
 	}
 
-	lastWriteTime, err := r.GetLastOverallWriteTimestamp(ctx)
+	count, err := r.readRdb.SInterCard(ctx, 0, keys...).Result()
+	if err != nil && strings.Contains(strings.ToLower(err.Error()), "unknown command") {
+		slog.Debug("SINTERCARD unsupported, counting with SINTER", "keys", keys)
+		members, err := r.readRdb.SInter(ctx, keys...).Result()
+		if err != nil {
+			return 0, fmt.Errorf("failed to intersect %v: %w", keys, err)
+		}
+		return int64(len(members)), nil
+	}
 	if err != nil {
-		slog.Warn("Could not get last overall write timestamp for GetModsByType", "modType", modType, "error", err)
+		return 0, fmt.Errorf("failed to count intersection of %v: %w", keys, err)
+	}
+	return count, nil
+}
+
+// addRemoveModIDCommands removes a mod known only by id (its blob is missing or unreadable)
+// from the type's id-keyed indexes. Title and tag entries need the mod data and are left to a rebuild.
+func (r *ModRepository) addRemoveModIDCommands(ctx context.Context, pipe redis.Pipeliner, modIDStr string, itemTypeTag string) {
+	modType := GetModTypeFromTag(itemTypeTag)
+	if modID, err := strconv.Atoi(modIDStr); err == nil {
+		r.addDeleteModBlobCommands(ctx, pipe, modID)
+	} else {
+		pipe.Del(ctx, r.keyPrefix+modKeyPrefix+modIDStr)
 	}
-	return mods, lastWriteTime, nil
+	pipe.SRem(ctx, r.keyPrefix+modTypeSetKeyPrefix+modType, modIDStr)
+	pipe.ZRem(ctx, r.keyPrefix+modDateUpdatedSortedSetKeyPrefix+modType, modIDStr)
+	pipe.ZRem(ctx, r.keyPrefix+modDateAddedSortedSetKeyPrefix+modType, modIDStr)
+	pipe.ZRem(ctx, r.keyPrefix+modCommentsSortedSetKeyPrefix+modType, modIDStr) // Harmless when comment indexing is disabled
+	pipe.ZRem(ctx, r.keyPrefix+modDownloadsSortedSetKeyPrefix+modType, modIDStr)
+	pipe.SRem(ctx, r.keyPrefix+modsWithMediaSetKeyPrefix+modType, modIDStr)
+}
+
+// ScanModsByType calls fn for every mod of a type, loading them in batches rather than all at once, in no
+// particular order. Iteration stops at the first error fn returns.
+func (r *ModRepository) ScanModsByType(ctx context.Context, modTypeTag string, fn func(*modio.Mod) error) error {
+	typeSetKey := r.keyPrefix + modTypeSetKeyPrefix + GetModTypeFromTag(modTypeTag)
+	seen := make(map[string]bool) // SSCAN may return an id more than once
+	var cursor uint64
+	for {
+		ids, nextCursor, err := r.readRdb.SScan(ctx, typeSetKey, cursor, "", mgetChunkSize).Result()
+		if err != nil {
+			return fmt.Errorf("failed to scan mod IDs for type %s: %w", modTypeTag, err)
+		}
+		batch := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				batch = append(batch, id)
+			}
+		}
+		mods, _, err := r.mgetMods(ctx, r.readRdb, batch)
+		if err != nil {
+			return fmt.Errorf("failed to get mods for type %s: %w", modTypeTag, err)
+		}
+		for _, mod := range mods {
+			if err := fn(mod); err != nil {
+				return err
+			}
+		}
+		cursor = nextCursor
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// StreamMods loads the mods with the given ids one MGET chunk at a time and calls fn for each as its chunk
+// arrives, so callers can write every chunk out before the next is read. It returns how many mods could not
+// be loaded; with partial results allowed, a failed chunk is dropped instead of failing the call. Ids
+// without a blob are skipped. An error from fn stops the iteration and is returned.
+func (r *ModRepository) StreamMods(ctx context.Context, ids []string, fn func(*modio.Mod) error) (int, error) {
+	dropped := 0
+	for start := 0; start < len(ids); start += mgetChunkSize {
+		mods, chunkDropped, err := r.getModsByIDs(ctx, ids[start:min(start+mgetChunkSize, len(ids))], r.allowPartialResults)
+		if err != nil {
+			return dropped, fmt.Errorf("failed to get mods by IDs: %w", err)
+		}
+		dropped += chunkDropped
+		for _, mod := range mods {
+			if err := fn(mod); err != nil {
+				return dropped, err
+			}
+		}
+	}
+	if dropped > 0 {
+		slog.Warn("Serving partial results", "dropped", dropped, "total", len(ids))
+	}
+	return dropped, nil
 }
 
 func (r *ModRepository) GetLastOverallWriteTimestamp(ctx context.Context) (time.Time, error) {
-	val, err := r.rdb.Get(ctx, systemLastOverallWriteTimestampKey).Result()
+	val, err := r.readRdb.Get(ctx, r.keyPrefix+systemLastOverallWriteTimestampKey).Result()
 	if err == redis.Nil {
 		return time.Time{}, nil
 	}
@@ -215,13 +733,31 @@ func (r *ModRepository) GetLastOverallWriteTimestamp(ctx context.Context) (time.
 
 func (r *ModRepository) SetLastOverallWriteTimestamp(ctx context.Context, t time.Time) error {
 	slog.Debug("Setting last overall write timestamp in Redis", "timestamp", t.Format(time.RFC3339Nano))
-	return r.rdb.Set(ctx, systemLastOverallWriteTimestampKey, t.Format(time.RFC3339Nano), 0).Err()
+	return r.rdb.Set(ctx, r.keyPrefix+systemLastOverallWriteTimestampKey, t.Format(time.RFC3339Nano), 0).Err()
+}
+
+// GetLastWriteTimestampForType returns when a full sync of the type last succeeded, or the zero time if
+// none has since per-type timestamps were introduced.
+func (r *ModRepository) GetLastWriteTimestampForType(ctx context.Context, modTypeTag string) (time.Time, error) {
+	val, err := r.readRdb.Get(ctx, r.keyPrefix+systemLastWriteTimestampKeyPrefix+GetModTypeFromTag(modTypeTag)).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339Nano, val)
+}
+
+func (r *ModRepository) SetLastWriteTimestampForType(ctx context.Context, modTypeTag string, t time.Time) error {
+	slog.Debug("Setting last write timestamp of type in Redis", "type", modTypeTag, "timestamp", t.Format(time.RFC3339Nano))
+	return r.rdb.Set(ctx, r.keyPrefix+systemLastWriteTimestampKeyPrefix+GetModTypeFromTag(modTypeTag), t.Format(time.RFC3339Nano), 0).Err()
 }
 
 func (r *ModRepository) GetSchedulerLastSyncEventTimestamp(ctx context.Context) (int64, error) {
-	val, err := r.rdb.Get(ctx, schedulerLastSyncEventTimestampKey).Result()
+	val, err := r.rdb.Get(ctx, r.keyPrefix+schedulerLastSyncEventTimestampKey).Result()
 	if err == redis.Nil {
-		slog.Info("Scheduler's last sync event timestamp not found in Redis.", "key", schedulerLastSyncEventTimestampKey)
+		slog.Info("Scheduler's last sync event timestamp not found in Redis.", "key", r.keyPrefix+schedulerLastSyncEventTimestampKey)
 		return 0, nil
 	}
 	if err != nil {
@@ -236,22 +772,183 @@ func (r *ModRepository) GetSchedulerLastSyncEventTimestamp(ctx context.Context)
 
 func (r *ModRepository) SetSchedulerLastSyncEventTimestamp(ctx context.Context, ts int64) error {
 	slog.Debug("Setting scheduler's last sync event timestamp in Redis", "timestamp", ts)
-	return r.rdb.Set(ctx, schedulerLastSyncEventTimestampKey, ts, 0).Err()
+	return r.rdb.Set(ctx, r.keyPrefix+schedulerLastSyncEventTimestampKey, ts, 0).Err()
+}
+
+// SetFullSyncTotals records, for one type, mod.io's result total and the cached mod count after a full sync.
+func (r *ModRepository) SetFullSyncTotals(ctx context.Context, modTypeTag string, modioTotal, cached int64) error {
+	modType := GetModTypeFromTag(modTypeTag)
+	return r.rdb.HSet(ctx, r.keyPrefix+fullSyncTotalsHashKey, modType+":modio", modioTotal, modType+":cached", cached).Err()
+}
+
+// GetFullSyncTotals returns the totals recorded by SetFullSyncTotals; ok is false if none were recorded.
+func (r *ModRepository) GetFullSyncTotals(ctx context.Context, modTypeTag string) (modioTotal, cached int64, ok bool, err error) {
+	modType := GetModTypeFromTag(modTypeTag)
+	vals, err := r.rdb.HMGet(ctx, r.keyPrefix+fullSyncTotalsHashKey, modType+":modio", modType+":cached").Result()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	parsed := make([]int64, len(vals))
+	for i, val := range vals {
+		str, isString := val.(string)
+		if !isString {
+			return 0, 0, false, nil
+		}
+		if parsed[i], err = strconv.ParseInt(str, 10, 64); err != nil {
+			return 0, 0, false, err
+		}
+	}
+	return parsed[0], parsed[1], true, nil
+}
+
+// GetRecentlyDeletedMods returns mods deleted strictly after since (unix seconds), oldest first.
+// Only the newest recentlyDeletedCap deletions are retained.
+func (r *ModRepository) GetRecentlyDeletedMods(ctx context.Context, since int64) ([]DeletedMod, error) {
+	results, err := r.readRdb.ZRangeByScoreWithScores(ctx, r.keyPrefix+recentlyDeletedSortedSetKey, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(since, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		slog.Error("Failed to get recently deleted mods from Redis", "since", since, "error", err)
+		return nil, err
+	}
+	deleted := make([]DeletedMod, 0, len(results))
+	for _, z := range results {
+		memberStr, _ := z.Member.(string)
+		modID, err := strconv.Atoi(memberStr)
+		if err != nil {
+			slog.Warn("Skipping malformed recently deleted entry", "member", z.Member)
+			continue
+		}
+		deleted = append(deleted, DeletedMod{ID: modID, DeletedAt: int64(z.Score)})
+	}
+	return deleted, nil
+}
+
+// FindProcessedEventIDs returns which of the given mod.io event ids were processed within the window.
+// Entries older than the window are trimmed first.
+func (r *ModRepository) FindProcessedEventIDs(ctx context.Context, eventIDs []int, window time.Duration) (map[int]bool, error) {
+	seen := make(map[int]bool)
+	if len(eventIDs) == 0 {
+		return seen, nil
+	}
+	members := make([]string, len(eventIDs))
+	for i, id := range eventIDs {
+		members[i] = strconv.Itoa(id)
+	}
+
+	pipe := r.rdb.Pipeline()
+	pipe.ZRemRangeByScore(ctx, r.keyPrefix+processedEventIDsSortedSetKey, "-inf", "("+strconv.FormatInt(time.Now().Add(-window).Unix(), 10))
+	scoresCmd := pipe.ZMScore(ctx, r.keyPrefix+processedEventIDsSortedSetKey, members...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to look up processed event ids: %w", err)
+	}
+	for i, score := range scoresCmd.Val() {
+		if score > 0 { // Unknown members score 0
+			seen[eventIDs[i]] = true
+		}
+	}
+	return seen, nil
+}
+
+// addMarkEventsProcessedCommands queues recording event ids as processed now. The whole set expires after
+// the window if processing stops, so it never outlives its usefulness.
+func (r *ModRepository) addMarkEventsProcessedCommands(ctx context.Context, pipe redis.Pipeliner, eventIDs []int, window time.Duration) {
+	if len(eventIDs) == 0 {
+		return
+	}
+	now := float64(time.Now().Unix())
+	members := make([]redis.Z, len(eventIDs))
+	for i, id := range eventIDs {
+		members[i] = redis.Z{Score: now, Member: strconv.Itoa(id)}
+	}
+	pipe.ZAdd(ctx, r.keyPrefix+processedEventIDsSortedSetKey, members...)
+	pipe.Expire(ctx, r.keyPrefix+processedEventIDsSortedSetKey, window)
+}
+
+// GetCachedTagOptions returns the cached game tag schema, or nil when it isn't cached or has expired.
+func (r *ModRepository) GetCachedTagOptions(ctx context.Context) ([]modio.ModioGameTagOption, error) {
+	val, err := r.readRdb.Get(ctx, r.keyPrefix+tagOptionsCacheKey).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var options []modio.ModioGameTagOption
+	if err := json.Unmarshal(val, &options); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cached tag options: %w", err)
+	}
+	return options, nil
 }
 
-func (r *ModRepository) SearchTitlesByPrefix(ctx context.Context, modTypeTag string, prefix string, count int) ([]string, error) {
+// SetCachedTagOptions caches the game tag schema for ttl.
+func (r *ModRepository) SetCachedTagOptions(ctx context.Context, options []modio.ModioGameTagOption, ttl time.Duration) error {
+	payload, err := json.Marshal(options)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tag options: %w", err)
+	}
+	return r.rdb.Set(ctx, r.keyPrefix+tagOptionsCacheKey, payload, ttl).Err()
+}
+
+// SyncCompletedEvent is published whenever a sync advances the generation.
+type SyncCompletedEvent struct {
+	Sync        string    `json:"sync"` // "events" or "full_sync"
+	Generation  int64     `json:"generation"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// PublishSyncCompleted announces a finished sync to every API instance subscribed via SubscribeSyncCompleted.
+func (r *ModRepository) PublishSyncCompleted(ctx context.Context, event SyncCompletedEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync completed event: %w", err)
+	}
+	return r.rdb.Publish(ctx, r.keyPrefix+syncCompletedChannel, payload).Err()
+}
+
+// SubscribeSyncCompleted subscribes to sync completion announcements. The caller must close the
+// returned subscription.
+func (r *ModRepository) SubscribeSyncCompleted(ctx context.Context) *redis.PubSub {
+	return r.rdb.Subscribe(ctx, r.keyPrefix+syncCompletedChannel)
+}
+
+// IncrementSyncGeneration atomically bumps the sync generation counter and returns the new value.
+// Unlike the write timestamp it can't collide for two syncs finishing within the same second.
+func (r *ModRepository) IncrementSyncGeneration(ctx context.Context) (int64, error) {
+	generation, err := r.rdb.Incr(ctx, r.keyPrefix+syncGenerationKey).Result()
+	if err != nil {
+		return 0, err
+	}
+	slog.Debug("Incremented sync generation in Redis", "generation", generation)
+	return generation, nil
+}
+
+// GetSyncGeneration returns the current sync generation, or 0 if no sync has completed yet.
+func (r *ModRepository) GetSyncGeneration(ctx context.Context) (int64, error) {
+	generation, err := r.readRdb.Get(ctx, r.keyPrefix+syncGenerationKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return generation, err
+}
+
+// SearchTitlesByPrefix returns up to count "normalizedtitle:id" members matching prefix, skipping the first
+// offset. Members are unique and ordered lexically, so equal titles are tie-broken by their id suffix and
+// consecutive pages neither repeat nor skip entries while the index is unchanged.
+func (r *ModRepository) SearchTitlesByPrefix(ctx context.Context, modTypeTag string, prefix string, offset, count int) ([]string, error) {
 	modType := GetModTypeFromTag(modTypeTag) // Use exported version
-	titleSortedSetKey := modTitleSortedSetKeyPrefix + modType
-	normalizedPrefix := normalizeStringForIndex(prefix)
+	titleSortedSetKey := r.keyPrefix + modTitleSortedSetKeyPrefix + modType
+	normalizedPrefix := r.normalize(prefix)
 
 	if normalizedPrefix == "" {
 		return []string{}, nil
 	}
 
-	results, err := r.rdb.ZRangeByLex(ctx, titleSortedSetKey, &redis.ZRangeBy{
+	results, err := r.readRdb.ZRangeByLex(ctx, titleSortedSetKey, &redis.ZRangeBy{
 		Min:    "[" + normalizedPrefix,
 		Max:    "[" + normalizedPrefix + "\xff",
-		Offset: 0,
+		Offset: int64(offset),
 		Count:  int64(count),
 	}).Result()
 
@@ -263,25 +960,40 @@ func (r *ModRepository) SearchTitlesByPrefix(ctx context.Context, modTypeTag str
 	return results, nil
 }
 
-func (r *ModRepository) RemoveOrphanedTagIndexEntries(ctx context.Context, pipe redis.Pipeliner, oldMod *modio.Mod, newMod *modio.Mod, itemTypeTag string) {
+// addRemoveOrphanedIndexCommands removes index entries the old version of a mod had but the new one no
+// longer needs: tag set memberships, a renamed title or slug and a previous submitter's author set.
+func (r *ModRepository) addRemoveOrphanedIndexCommands(ctx context.Context, pipe redis.Pipeliner, oldMod *modio.Mod, newMod *modio.Mod, itemTypeTag string) {
 	modType := GetModTypeFromTag(itemTypeTag) // Use exported version
 	modIDStr := strconv.Itoa(oldMod.ID)
 
+	if oldMod.NameID != "" && (newMod == nil || normalizeStringForIndex(newMod.NameID) != normalizeStringForIndex(oldMod.NameID)) {
+		pipe.HDel(ctx, r.keyPrefix+modSlugHashKey, normalizeStringForIndex(oldMod.NameID))
+	}
+	if oldMod.SubmittedBy.ID != 0 && (newMod == nil || newMod.SubmittedBy.ID != oldMod.SubmittedBy.ID) {
+		pipe.SRem(ctx, r.keyPrefix+modAuthorSetKeyPrefix+strconv.Itoa(oldMod.SubmittedBy.ID), modIDStr)
+	}
+	if oldTitle := r.normalize(oldMod.Name); newMod == nil || r.normalize(newMod.Name) != oldTitle {
+		pipe.ZRem(ctx, r.keyPrefix+modTitleSortedSetKeyPrefix+modType, fmt.Sprintf("%s:%s", oldTitle, modIDStr))
+	}
+	r.addRemoveSearchWordCommands(ctx, pipe, oldMod, newMod, modType)
+
 	oldTags := make(map[string]bool)
 	for _, tag := range oldMod.Tags {
-		oldTags[normalizeStringForIndex(tag.Name)] = true
+		oldTags[r.normalize(tag.Name)] = true
 	}
 
 	newTags := make(map[string]bool)
 	if newMod != nil {
 		for _, tag := range newMod.Tags {
-			newTags[normalizeStringForIndex(tag.Name)] = true
+			if normalizedTagName := r.normalize(tag.Name); r.isTagIndexed(normalizedTagName) {
+				newTags[normalizedTagName] = true
+			}
 		}
 	}
 
 	for oldTagName := range oldTags {
 		if !newTags[oldTagName] {
-			tagSetKey := fmt.Sprintf("%s%s:%s", modTagSetKeyPrefix, oldTagName, modType)
+			tagSetKey := fmt.Sprintf("%s%s:%s", r.keyPrefix+modTagSetKeyPrefix, oldTagName, modType)
 			pipe.SRem(ctx, tagSetKey, modIDStr)
 			slog.Debug("Adding command to remove mod from orphaned tag set", "mod_id", modIDStr, "tag", oldTagName, "type", modType)
 		}
@@ -290,14 +1002,61 @@ func (r *ModRepository) RemoveOrphanedTagIndexEntries(ctx context.Context, pipe
 
 func (r *ModRepository) GetModIDsByTag(ctx context.Context, modTypeTag string, tagName string) ([]string, error) {
 	modType := GetModTypeFromTag(modTypeTag) // Use exported version
-	normalizedTagName := normalizeStringForIndex(tagName)
-	tagSetKey := fmt.Sprintf("%s%s:%s", modTagSetKeyPrefix, normalizedTagName, modType)
+	normalizedTagName := r.normalize(tagName)
+	tagSetKey := fmt.Sprintf("%s%s:%s", r.keyPrefix+modTagSetKeyPrefix, normalizedTagName, modType)
 
 	slog.Debug("Fetching mod IDs by tag from Redis", "key", tagSetKey)
-	ids, err := r.rdb.SMembers(ctx, tagSetKey).Result()
+	ids, err := r.readRdb.SMembers(ctx, tagSetKey).Result()
 	if err != nil {
 		slog.Error("Failed to get mod IDs by tag from Redis", "key", tagSetKey, "error", err)
 		return nil, err
 	}
 	return ids, nil
 }
+
+// GetModsByAuthorUpdatedSince returns the mods submitted by a user, newest update first. A positive
+// updatedSince keeps only mods whose date_updated is after it, checked against the per-type date
+// indexes so only the surviving blobs are loaded.
+func (r *ModRepository) GetModsByAuthorUpdatedSince(ctx context.Context, userID int, updatedSince int64) ([]*modio.Mod, error) {
+	authorSetKey := r.keyPrefix + modAuthorSetKeyPrefix + strconv.Itoa(userID)
+	ids, err := r.readRdb.SMembers(ctx, authorSetKey).Result()
+	if err != nil {
+		slog.Error("Failed to get mod IDs by author from Redis", "key", authorSetKey, "error", err)
+		return nil, err
+	}
+
+	if updatedSince > 0 && len(ids) > 0 {
+		pipe := r.readRdb.Pipeline()
+		typeTags := r.IndexedTypeTags()
+		scoreCmds := make([]*redis.FloatSliceCmd, 0, len(typeTags))
+		for _, typeTag := range typeTags {
+			scoreCmds = append(scoreCmds, pipe.ZMScore(ctx, r.keyPrefix+modDateUpdatedSortedSetKeyPrefix+GetModTypeFromTag(typeTag), ids...))
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			slog.Error("Failed to score author mods against date indexes", "key", authorSetKey, "error", err)
+			return nil, err
+		}
+		updatedIDs := make([]string, 0, len(ids))
+		for i, id := range ids {
+			for _, cmd := range scoreCmds {
+				if cmd.Val()[i] > float64(updatedSince) { // Ids missing from a type's index score 0
+					updatedIDs = append(updatedIDs, id)
+					break
+				}
+			}
+		}
+		ids = updatedIDs
+	}
+
+	mods, err := r.GetModsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(mods, func(i, j int) bool {
+		if mods[i].DateUpdated != mods[j].DateUpdated {
+			return mods[i].DateUpdated > mods[j].DateUpdated
+		}
+		return mods[i].ID > mods[j].ID // Same order as the date index pages, see newestFirstPage
+	})
+	return mods, nil
+}
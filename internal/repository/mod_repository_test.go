@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/config"
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+const testGameID = "629"
+
+// newTestRepository returns a repository over a fresh miniredis, configured like a default deployment
+// (Map and Script categories, untyped mods skipped) with whatever changes configure makes.
+func newTestRepository(t *testing.T, configure func(*config.AppConfig)) *ModRepository {
+	t.Helper()
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	cfg := &config.AppConfig{
+		ModioGameID:            testGameID,
+		ModCategories:          []string{"Map", "Script"},
+		UntypedMods:            config.UntypedModsSkip,
+		StoreModfileChangelogs: true,
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+	return NewModRepository(rdb, nil, cfg)
+}
+
+// testMod returns a mod with the given name and tags, a slug derived from its id and a fixed submitter.
+func testMod(id int, name string, tags ...string) *modio.Mod {
+	mod := &modio.Mod{
+		ID:          id,
+		Name:        name,
+		NameID:      fmt.Sprintf("mod-%d", id),
+		SubmittedBy: modio.ModioUser{ID: 42, Username: "tester"},
+		DateAdded:   1700000000 + int64(id),
+		DateUpdated: 1700000000 + int64(id),
+		Stats:       modio.ModioStats{DownloadsTotal: 10 * id},
+	}
+	for _, tag := range tags {
+		mod.Tags = append(mod.Tags, modio.ModioTag{Name: tag})
+	}
+	return mod
+}
+
+// applyTestChanges records changes with record and applies them, failing the test on error.
+func applyTestChanges(t *testing.T, repo *ModRepository, record func(*ChangeSet)) ChangeSummary {
+	t.Helper()
+	changes := &ChangeSet{}
+	record(changes)
+	summary, err := repo.ApplyChanges(context.Background(), changes)
+	if err != nil {
+		t.Fatalf("ApplyChanges: %v", err)
+	}
+	return summary
+}
+
+// indexMembership reports, per index of modType, whether mod is a member of it. Tag sets are checked for
+// the mod's own tags; pass the stored copy to check the entries it was indexed with.
+func indexMembership(t *testing.T, repo *ModRepository, mod *modio.Mod, modType string) map[string]bool {
+	t.Helper()
+	ctx := context.Background()
+	rdb := repo.rdb
+	idStr := strconv.Itoa(mod.ID)
+	member := make(map[string]bool)
+
+	isMember := func(name, key string) {
+		ok, err := rdb.SIsMember(ctx, key, idStr).Result()
+		if err != nil {
+			t.Fatalf("SISMEMBER %s: %v", key, err)
+		}
+		member[name] = ok
+	}
+	hasScore := func(name, key, zMember string) {
+		_, err := rdb.ZScore(ctx, key, zMember).Result()
+		if err != nil && err != redis.Nil {
+			t.Fatalf("ZSCORE %s: %v", key, err)
+		}
+		member[name] = err == nil
+	}
+
+	isMember("type set", repo.keyPrefix+modTypeSetKeyPrefix+modType)
+	hasScore("title", repo.keyPrefix+modTitleSortedSetKeyPrefix+modType, repo.normalize(mod.Name)+":"+idStr)
+	hasScore("date updated", repo.keyPrefix+modDateUpdatedSortedSetKeyPrefix+modType, idStr)
+	hasScore("date added", repo.keyPrefix+modDateAddedSortedSetKeyPrefix+modType, idStr)
+	hasScore("downloads", repo.keyPrefix+modDownloadsSortedSetKeyPrefix+modType, idStr)
+	for _, tag := range mod.Tags {
+		isMember("tag "+tag.Name, fmt.Sprintf("%s%s:%s", repo.keyPrefix+modTagSetKeyPrefix, repo.normalize(tag.Name), modType))
+	}
+	for word := range repo.modSearchWords(mod) {
+		isMember("word "+word, repo.wordSetKey(word, modType))
+	}
+	return member
+}
+
+func assertIndexed(t *testing.T, repo *ModRepository, mod *modio.Mod, modType string, want bool) {
+	t.Helper()
+	for name, indexed := range indexMembership(t, repo, mod, modType) {
+		if indexed != want {
+			t.Errorf("mod %d in %s %s index: got %v, want %v", mod.ID, modType, name, indexed, want)
+		}
+	}
+}
+
+func TestApplyChangesRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		mod     *modio.Mod
+		modType string
+	}{
+		{"map", testMod(1, "Downtown Plaza", "Map", "Street"), "map"},
+		{"script", testMod(2, "Better Grinds", "Script", "Gameplay"), "script"},
+		{"lowercase type tag", testMod(3, "Night Park", "map", "Park"), "map"},
+		{"title with colons", testMod(4, "Park: Part 2", "Map"), "map"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newTestRepository(t, nil)
+
+			applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(tt.mod, "") })
+			assertIndexed(t, repo, tt.mod, tt.modType, true)
+			stored, err := repo.GetModByID(ctx, tt.mod.ID)
+			if err != nil || stored == nil {
+				t.Fatalf("GetModByID after upsert: mod %v, err %v", stored, err)
+			}
+			if stored.Name != tt.mod.Name {
+				t.Errorf("stored name: got %q, want %q", stored.Name, tt.mod.Name)
+			}
+			slugs, err := repo.ResolveSlugs(ctx, []string{tt.mod.NameID})
+			if err != nil {
+				t.Fatalf("ResolveSlugs: %v", err)
+			}
+			if got := slugs[tt.mod.NameID]; got != strconv.Itoa(tt.mod.ID) {
+				t.Errorf("slug %q resolves to %q, want %d", tt.mod.NameID, got, tt.mod.ID)
+			}
+
+			summary := applyTestChanges(t, repo, func(c *ChangeSet) { c.Delete(tt.mod.ID, "") })
+			if len(summary.DeletedIDs) != 1 || summary.DeletedIDs[0] != tt.mod.ID {
+				t.Errorf("DeletedIDs: got %v, want [%d]", summary.DeletedIDs, tt.mod.ID)
+			}
+			assertIndexed(t, repo, tt.mod, tt.modType, false)
+			if stored, err := repo.GetModByID(ctx, tt.mod.ID); err != nil || stored != nil {
+				t.Errorf("GetModByID after delete: mod %v, err %v", stored, err)
+			}
+			if slugs, err := repo.ResolveSlugs(ctx, []string{tt.mod.NameID}); err != nil || slugs[tt.mod.NameID] != "" {
+				t.Errorf("slug %q after delete: resolves to %q, err %v", tt.mod.NameID, slugs[tt.mod.NameID], err)
+			}
+			author, err := repo.rdb.SIsMember(ctx, repo.keyPrefix+modAuthorSetKeyPrefix+"42", strconv.Itoa(tt.mod.ID)).Result()
+			if err != nil || author {
+				t.Errorf("author set membership after delete: got %v, err %v", author, err)
+			}
+		})
+	}
+}
+
+func TestApplyChangesRemovesOrphanedTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldTags   []string
+		newTags   []string
+		wantGone  []string
+		wantKept  []string
+		wantAdded []string
+	}{
+		{"tag replaced", []string{"Map", "Park", "Street"}, []string{"Map", "Park", "Rails"}, []string{"Street"}, []string{"Park"}, []string{"Rails"}},
+		{"all extra tags removed", []string{"Map", "Park", "Street"}, []string{"Map"}, []string{"Park", "Street"}, nil, nil},
+		{"tag case changed", []string{"Map", "Park"}, []string{"Map", "PARK"}, nil, []string{"Park"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newTestRepository(t, nil)
+			applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(testMod(1, "Plaza", tt.oldTags...), "") })
+			applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(testMod(1, "Plaza", tt.newTags...), "") })
+
+			check := func(tags []string, want bool) {
+				for _, tag := range tags {
+					ids, err := repo.GetModIDsByTag(ctx, "Map", tag)
+					if err != nil {
+						t.Fatalf("GetModIDsByTag %q: %v", tag, err)
+					}
+					if got := len(ids) == 1 && ids[0] == "1"; got != want {
+						t.Errorf("tag %q set: got ids %v, want mod 1 present %v", tag, ids, want)
+					}
+				}
+			}
+			check(tt.wantGone, false)
+			check(tt.wantKept, true)
+			check(tt.wantAdded, true)
+		})
+	}
+}
+
+func TestApplyChangesRename(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, nil)
+	old := testMod(1, "Old Plaza", "Map")
+	old.NameID = "old-plaza"
+	renamed := testMod(1, "New Plaza", "Map")
+	renamed.NameID = "new-plaza"
+
+	applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(old, "") })
+	applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(renamed, "") })
+
+	titleKey := repo.keyPrefix + modTitleSortedSetKeyPrefix + "map"
+	titles, err := repo.rdb.ZRange(ctx, titleKey, 0, -1).Result()
+	if err != nil {
+		t.Fatalf("ZRANGE %s: %v", titleKey, err)
+	}
+	if len(titles) != 1 || titles[0] != "new plaza:1" {
+		t.Errorf("title members: got %v, want [new plaza:1]", titles)
+	}
+	matches, err := repo.SearchTitlesByPrefix(ctx, "Map", "old", 0, 10)
+	if err != nil {
+		t.Fatalf("SearchTitlesByPrefix: %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("old title still autocompletes: %v", matches)
+	}
+
+	slugs, err := repo.ResolveSlugs(ctx, []string{"old-plaza", "new-plaza"})
+	if err != nil {
+		t.Fatalf("ResolveSlugs: %v", err)
+	}
+	if slugs["old-plaza"] != "" || slugs["new-plaza"] != "1" {
+		t.Errorf("slugs after rename: got %v, want only new-plaza -> 1", slugs)
+	}
+	if words := indexMembership(t, repo, old, "map"); words["word old"] {
+		t.Errorf("old title word still indexed")
+	}
+}
+
+func TestApplyChangesTypeSetMembership(t *testing.T) {
+	tests := []struct {
+		name      string
+		mod       *modio.Mod
+		typeTag   string // Passed to Upsert; empty derives it from the tags
+		wantTypes []string
+	}{
+		{"map tag", testMod(1, "Plaza", "Map"), "", []string{"map"}},
+		{"script tag", testMod(1, "Grinds", "Script"), "", []string{"script"}},
+		{"first type tag wins", testMod(1, "Hybrid", "Script", "Map"), "", []string{"script"}},
+		{"explicit type", testMod(1, "Hybrid", "Map", "Script"), "Script", []string{"script"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newTestRepository(t, nil)
+			applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(tt.mod, tt.typeTag) })
+
+			for _, modType := range []string{"map", "script"} {
+				ids, err := repo.GetAllModIDsByType(ctx, modType)
+				if err != nil {
+					t.Fatalf("GetAllModIDsByType %s: %v", modType, err)
+				}
+				want := false
+				for _, wantType := range tt.wantTypes {
+					want = want || wantType == modType
+				}
+				if got := len(ids) == 1 && ids[0] == "1"; got != want {
+					t.Errorf("%s type set: got %v, want mod 1 present %v", modType, ids, want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyChangesTypeScopedDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t, nil)
+	mod := testMod(1, "Plaza", "Map")
+	applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(mod, "") })
+
+	// No other type holds the mod, so removing it from its only type deletes the blob too.
+	applyTestChanges(t, repo, func(c *ChangeSet) { c.Delete(mod.ID, "Map") })
+	assertIndexed(t, repo, mod, "map", false)
+	if stored, err := repo.GetModByID(ctx, mod.ID); err != nil || stored != nil {
+		t.Errorf("GetModByID after type delete: mod %v, err %v", stored, err)
+	}
+	deleted, err := repo.GetRecentlyDeletedMods(ctx, 0)
+	if err != nil {
+		t.Fatalf("GetRecentlyDeletedMods: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].ID != mod.ID {
+		t.Errorf("recently deleted: got %v, want mod %d", deleted, mod.ID)
+	}
+}
+
+func TestModfileChangelogRoundTrip(t *testing.T) {
+	tests := []struct {
+		name            string
+		changelog       string
+		storeChangelogs bool
+		want            string
+	}{
+		{"changelog kept", "Fixed the rails\nAdded a bowl", true, "Fixed the rails\nAdded a bowl"},
+		{"no changelog", "", true, ""},
+		{"changelogs not stored", "Fixed the rails", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newTestRepository(t, func(cfg *config.AppConfig) { cfg.StoreModfileChangelogs = tt.storeChangelogs })
+			mod := testMod(1, "Plaza", "Map")
+			mod.Modfile = modio.ModioModfile{ID: 7, Version: "1.2", Changelog: tt.changelog}
+			applyTestChanges(t, repo, func(c *ChangeSet) { c.Upsert(mod, "") })
+
+			stored, err := repo.GetModByID(ctx, mod.ID)
+			if err != nil || stored == nil {
+				t.Fatalf("GetModByID: mod %v, err %v", stored, err)
+			}
+			if stored.Modfile.Changelog != tt.want {
+				t.Errorf("changelog: got %q, want %q", stored.Modfile.Changelog, tt.want)
+			}
+			if stored.Modfile.ID != 7 || stored.Modfile.Version != "1.2" {
+				t.Errorf("modfile: got id %d version %q, want 7 and 1.2", stored.Modfile.ID, stored.Modfile.Version)
+			}
+			blob, err := repo.rdb.Get(ctx, repo.keyPrefix+modKeyPrefix+"1").Result()
+			if err != nil {
+				t.Fatalf("GET mod blob: %v", err)
+			}
+			if tt.want == "" && strings.Contains(blob, `"changelog"`) {
+				t.Errorf("blob carries an empty changelog: %s", blob)
+			}
+		})
+	}
+}
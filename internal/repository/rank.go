@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Rank metrics accepted by GetModRank.
+const (
+	RankMetricDownloads = "downloads"
+	RankMetricUpdated   = "updated"
+	RankMetricComments  = "comments"
+)
+
+// ErrUnknownRankMetric is returned for a metric without a sorted index, including comments while
+// INDEX_COMMENT_COUNTS is off.
+var ErrUnknownRankMetric = errors.New("unknown rank metric")
+
+// ModRank is a mod's 1-based position within its type, highest metric first.
+type ModRank struct {
+	Rank  int64 `json:"rank"`
+	Total int64 `json:"total"`
+}
+
+// GetModRank returns a mod's position in a type's sorted index for metric, or nil if the mod isn't in it.
+func (r *ModRepository) GetModRank(ctx context.Context, modTypeTag string, metric string, modID int) (*ModRank, error) {
+	var prefix string
+	switch {
+	case metric == RankMetricDownloads:
+		prefix = r.keyPrefix + modDownloadsSortedSetKeyPrefix
+	case metric == RankMetricUpdated:
+		prefix = r.keyPrefix + modDateUpdatedSortedSetKeyPrefix
+	case metric == RankMetricComments && r.indexCommentCounts:
+		prefix = r.keyPrefix + modCommentsSortedSetKeyPrefix
+	default:
+		return nil, ErrUnknownRankMetric
+	}
+	sortedSetKey := prefix + GetModTypeFromTag(modTypeTag)
+
+	pipe := r.readRdb.Pipeline()
+	rankCmd := pipe.ZRevRank(ctx, sortedSetKey, strconv.Itoa(modID))
+	totalCmd := pipe.ZCard(ctx, sortedSetKey)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to rank mod %d in %s: %w", modID, sortedSetKey, err)
+	}
+	if rankCmd.Err() == redis.Nil {
+		return nil, nil
+	}
+	rank := rankCmd.Val()
+	if metric == RankMetricUpdated {
+		// ZREVRANK orders mods updated in the same second by member bytes; rank them as the lists order them.
+		var err error
+		rank, err = newestFirstRank(ctx, r.readRdb, sortedSetKey, strconv.Itoa(modID))
+		if err == redis.Nil {
+			return nil, nil // Removed since the first read
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to rank mod %d in %s: %w", modID, sortedSetKey, err)
+		}
+	}
+	return &ModRank{Rank: rank + 1, Total: totalCmd.Val()}, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/redis/go-redis/v9"
+)
+
+// Search orders accepted by SearchModIDs.
+const (
+	SearchOrderDownloads = "downloads"
+	SearchOrderUpdated   = "updated"
+)
+
+// ErrUnknownSearchOrder is returned by SearchModIDs for an order other than the SearchOrder* ones.
+var ErrUnknownSearchOrder = errors.New("unknown search order")
+
+// minSearchTokenRunes drops one-letter words, which would put nearly every mod in a handful of huge sets.
+const minSearchTokenRunes = 2
+
+// SearchTokens splits text, such as a search query, into the distinct normalized words the search index
+// uses.
+func (r *ModRepository) SearchTokens(text string) []string {
+	fields := strings.FieldsFunc(r.normalize(text), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	seen := make(map[string]bool, len(fields))
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if len([]rune(field)) >= minSearchTokenRunes && !seen[field] {
+			seen[field] = true
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
+
+// modSearchWords returns the set of words a mod is indexed under: those of its name and summary.
+func (r *ModRepository) modSearchWords(mod *modio.Mod) map[string]bool {
+	words := make(map[string]bool)
+	for _, text := range []string{mod.Name, mod.Summary} {
+		for _, token := range r.SearchTokens(text) {
+			words[token] = true
+		}
+	}
+	return words
+}
+
+func (r *ModRepository) wordSetKey(word, modType string) string {
+	return r.keyPrefix + modWordSetKeyPrefix + word + ":" + modType
+}
+
+// SearchModIDs returns a page of the ids of a type's mods whose name or summary contains every token,
+// ranked by downloads or last update (highest first), and the total number of matches. The word sets are
+// intersected with the rank index into a short-lived temp key on the primary.
+func (r *ModRepository) SearchModIDs(ctx context.Context, modTypeTag string, tokens []string, order string, limit, offset int) ([]string, int64, error) {
+	modType := GetModTypeFromTag(modTypeTag)
+	var rankKey string
+	switch order {
+	case SearchOrderDownloads:
+		rankKey = r.keyPrefix + modDownloadsSortedSetKeyPrefix + modType
+	case SearchOrderUpdated:
+		rankKey = r.keyPrefix + modDateUpdatedSortedSetKeyPrefix + modType
+	default:
+		return nil, 0, ErrUnknownSearchOrder
+	}
+	if len(tokens) == 0 {
+		return []string{}, 0, nil
+	}
+
+	keys := []string{rankKey}
+	weights := []float64{1}
+	for _, token := range tokens {
+		keys = append(keys, r.wordSetKey(token, modType))
+		weights = append(weights, 0)
+	}
+	tempKey := fmt.Sprintf("%s%s:%d", r.keyPrefix+tempSearchKeyPrefix, modType, time.Now().UnixNano())
+	defer func() {
+		if err := r.rdb.Del(context.WithoutCancel(ctx), tempKey).Err(); err != nil {
+			slog.Warn("Failed to delete temp search key, it expires on its own", "key", tempKey, "error", err)
+		}
+	}()
+
+	pipe := r.rdb.Pipeline()
+	totalCmd := pipe.ZInterStore(ctx, tempKey, &redis.ZStore{Keys: keys, Weights: weights})
+	pipe.Expire(ctx, tempKey, tempKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, fmt.Errorf("failed to search %s mods for %v: %w", modType, tokens, err)
+	}
+	ids, err := newestFirstPage(ctx, r.rdb, tempKey, "-inf", limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search %s mods for %v: %w", modType, tokens, err)
+	}
+	return ids, totalCmd.Val(), nil
+}
+
+// addSearchWordCommands queues adding a mod to the word sets of its name and summary.
+func (r *ModRepository) addSearchWordCommands(ctx context.Context, pipe redis.Pipeliner, mod *modio.Mod, modType string) {
+	modIDStr := strconv.Itoa(mod.ID)
+	for word := range r.modSearchWords(mod) {
+		pipe.SAdd(ctx, r.wordSetKey(word, modType), modIDStr)
+	}
+}
+
+// addRemoveSearchWordCommands queues removing oldMod from the word sets newMod no longer has words for;
+// a nil newMod removes it from all of them.
+func (r *ModRepository) addRemoveSearchWordCommands(ctx context.Context, pipe redis.Pipeliner, oldMod, newMod *modio.Mod, modType string) {
+	var newWords map[string]bool
+	if newMod != nil {
+		newWords = r.modSearchWords(newMod)
+	}
+	modIDStr := strconv.Itoa(oldMod.ID)
+	for word := range r.modSearchWords(oldMod) {
+		if !newWords[word] {
+			pipe.SRem(ctx, r.wordSetKey(word, modType), modIDStr)
+		}
+	}
+}
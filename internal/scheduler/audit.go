@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+)
+
+const integrityAuditTimeout = 5 * time.Minute
+
+// IntegrityAuditResult is the outcome of the latest background integrity audit.
+type IntegrityAuditResult struct {
+	repository.IntegrityAudit
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// runIntegrityAudit samples every type index and the next stretch of mod blobs and logs the divergences
+// found. Nothing is repaired. The audit is skipped while a sync holds updateMu, since half-applied
+// writes would count as divergences.
+func (s *Scheduler) runIntegrityAudit(ctx context.Context) {
+	if !s.updateMu.TryLock() {
+		slog.Info("Scheduler (Audit): Sync in progress, skipping integrity audit.")
+		return
+	}
+	defer s.updateMu.Unlock()
+
+	sampleSize := s.cfg.IntegrityAuditSampleSize
+	var audit repository.IntegrityAudit
+	for _, typeTag := range s.modRepo.IndexedTypeTags() {
+		typeAudit, err := s.modRepo.AuditTypeIndex(ctx, typeTag, sampleSize)
+		if err != nil {
+			slog.Error("Scheduler (Audit): Failed to audit type index", "type", typeTag, "error", err)
+			return
+		}
+		audit.Add(typeAudit)
+	}
+	blobAudit, cursor, err := s.modRepo.AuditBlobs(ctx, s.auditCursor, sampleSize)
+	if err != nil {
+		slog.Error("Scheduler (Audit): Failed to audit mod blobs", "error", err)
+		return
+	}
+	s.auditCursor = cursor
+	audit.Add(blobAudit)
+
+	s.auditMu.Lock()
+	s.lastAudit = &IntegrityAuditResult{IntegrityAudit: audit, CompletedAt: time.Now().UTC()}
+	s.auditMu.Unlock()
+
+	args := []any{
+		"sampled_index_entries", audit.SampledIndexEntries,
+		"index_entries_without_blob", audit.IndexEntriesWithoutBlob,
+		"sampled_blobs", audit.SampledBlobs,
+		"blobs_missing_from_index", audit.BlobsMissingFromIndex,
+	}
+	if audit.IndexEntriesWithoutBlob > 0 || audit.BlobsMissingFromIndex > 0 {
+		slog.Warn("Scheduler (Audit): Integrity audit found divergences, a full sync will repair them.", args...)
+		return
+	}
+	slog.Info("Scheduler (Audit): Integrity audit found no divergences.", args...)
+}
+
+// LastIntegrityAudit returns the latest completed integrity audit, or nil if none has run.
+func (s *Scheduler) LastIntegrityAudit() *IntegrityAuditResult {
+	s.auditMu.Lock()
+	defer s.auditMu.Unlock()
+	if s.lastAudit == nil {
+		return nil
+	}
+	result := *s.lastAudit
+	return &result
+}
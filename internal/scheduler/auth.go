@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+)
+
+// AuthFailure records mod.io rejecting the API key (401/403). Scheduled syncs are skipped until RetryAt
+// instead of failing every request; admin-triggered syncs still run, so a fixed key can be tried at once.
+type AuthFailure struct {
+	DetectedAt time.Time `json:"detectedAt"`
+	RetryAt    time.Time `json:"retryAt"`
+	Error      string    `json:"error"`
+}
+
+// noteAuthFailure records err as an auth failure if mod.io rejected the API key, and reports whether it did.
+func (s *Scheduler) noteAuthFailure(err error) bool {
+	if !errors.Is(err, modio.ErrUnauthorized) {
+		return false
+	}
+	now := time.Now().UTC()
+	failure := &AuthFailure{DetectedAt: now, RetryAt: now.Add(s.cfg.AuthFailureCooldown), Error: err.Error()}
+	s.authMu.Lock()
+	s.authFailure = failure
+	s.authMu.Unlock()
+	slog.Error("Scheduler: mod.io rejected the API key, stopping the sync and pausing scheduled syncs. Check MODIO_API_KEY.",
+		"retry_at", failure.RetryAt.Format(time.RFC3339), "error", err)
+	return true
+}
+
+// ClearAuthFailure forgets a recorded auth failure, after mod.io accepted a request again or the API key
+// was rotated.
+func (s *Scheduler) ClearAuthFailure() {
+	s.authMu.Lock()
+	defer s.authMu.Unlock()
+	if s.authFailure != nil {
+		slog.Info("Scheduler: Cleared mod.io auth failure, resuming scheduled syncs.")
+		s.authFailure = nil
+	}
+}
+
+// logRateLimit logs the mod.io rate limit as last reported, if it ever was.
+func (s *Scheduler) logRateLimit(logPrefix string) {
+	status := s.modioClient.RateLimitStatus()
+	if status == nil {
+		return
+	}
+	slog.Info(logPrefix+" Mod.io rate limit.", "limit", status.Limit, "remaining", status.Remaining,
+		"retry_after", status.RetryAfter, "observed_at", status.ObservedAt.UTC().Format(time.RFC3339))
+}
+
+// inAuthCooldown reports whether a scheduled sync should be skipped because of a recent auth failure.
+func (s *Scheduler) inAuthCooldown(triggeredBy string) bool {
+	failure := s.AuthFailure()
+	if failure == nil || !time.Now().Before(failure.RetryAt) {
+		return false
+	}
+	slog.Warn("Scheduler: Skipping sync while the mod.io API key is failing.", "triggered_by", triggeredBy, "retry_at", failure.RetryAt.Format(time.RFC3339))
+	return true
+}
+
+// AuthFailure returns the current mod.io auth failure, or nil while the API key is accepted.
+func (s *Scheduler) AuthFailure() *AuthFailure {
+	s.authMu.Lock()
+	defer s.authMu.Unlock()
+	if s.authFailure == nil {
+		return nil
+	}
+	failure := *s.authFailure
+	return &failure
+}
@@ -0,0 +1,53 @@
+package scheduler
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const cacheWarmRequestTimeout = 10 * time.Second
+
+var cacheWarmClient = &http.Client{Timeout: cacheWarmRequestTimeout}
+
+// warmCaches requests every configured cache warm target in the background after a sync wrote data. It
+// never blocks or fails the sync: errors are logged, and a run is skipped while the previous one is still
+// going, since that run already sees the new data.
+func (s *Scheduler) warmCaches(generation int64) {
+	if len(s.cfg.CacheWarmTargets) == 0 {
+		return
+	}
+	if !s.warmingCaches.CompareAndSwap(false, true) {
+		slog.Debug("Scheduler (Cache Warm): Previous run still in progress, skipping.", "generation", generation)
+		return
+	}
+	go func() {
+		defer s.warmingCaches.Store(false)
+		ctx := s.baseContext()
+		for _, target := range s.cfg.CacheWarmTargets {
+			req, err := http.NewRequestWithContext(ctx, target.Method, target.URL, nil)
+			if err != nil {
+				slog.Warn("Scheduler (Cache Warm): Failed to create request", "method", target.Method, "url", target.URL, "error", err)
+				continue
+			}
+			req.Header.Set("X-Sync-Generation", strconv.FormatInt(generation, 10))
+			if s.cfg.CacheWarmToken != "" {
+				req.Header.Set("Authorization", "Bearer "+s.cfg.CacheWarmToken)
+			}
+			resp, err := cacheWarmClient.Do(req)
+			if err != nil {
+				slog.Warn("Scheduler (Cache Warm): Request failed", "method", target.Method, "url", target.URL, "error", err)
+				continue
+			}
+			io.Copy(io.Discard, resp.Body) // A self-GET only repopulates the CDN once the body is read
+			resp.Body.Close()
+			if resp.StatusCode >= http.StatusBadRequest {
+				slog.Warn("Scheduler (Cache Warm): Request rejected", "method", target.Method, "url", target.URL, "status", resp.StatusCode)
+				continue
+			}
+			slog.Debug("Scheduler (Cache Warm): Request succeeded", "method", target.Method, "url", target.URL, "status", resp.StatusCode)
+		}
+	}()
+}
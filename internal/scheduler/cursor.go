@@ -0,0 +1,104 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// cursorClockSkew is how far ahead of the local clock the event cursor may be before it's considered
+// impossible. mod.io timestamps come from its clock, not ours.
+const cursorClockSkew = 5 * time.Minute
+
+// reconciliationSyncTimeout bounds the full sync run for a stale cursor, as long as a scheduled full sync
+// gets; the event cycle's own deadline is far too short for one.
+const reconciliationSyncTimeout = 30 * time.Minute
+
+// ErrInvalidCursor is returned when a manual cursor reset asks for a timestamp in the future.
+var ErrInvalidCursor = errors.New("event cursor timestamp is in the future")
+
+// CursorReset reports an event cursor change.
+type CursorReset struct {
+	Previous int64 `json:"previous"`
+	Current  int64 `json:"current"`
+}
+
+func cursorIsImpossible(ts int64, now time.Time) bool {
+	return ts < 0 || ts > now.Add(cursorClockSkew).Unix()
+}
+
+// repairedCursor is where an impossible cursor is moved back to: now minus the configured lookback, so
+// events from just before the glitch are replayed (the dedup window skips ones already applied).
+func (s *Scheduler) repairedCursor(now time.Time) int64 {
+	return now.Add(-s.cfg.EventCursorRepairLookback).Unix()
+}
+
+// ValidateEventCursor repairs an event cursor set to a negative or future timestamp. Left alone, such a
+// cursor makes every event fetch return nothing and the catalog silently stops updating.
+func (s *Scheduler) ValidateEventCursor(ctx context.Context) {
+	ts, err := s.modRepo.GetSchedulerLastSyncEventTimestamp(ctx)
+	if err != nil {
+		logSyncError(eventsLogPrefix, "Failed to read event cursor for validation.", err)
+		return
+	}
+	s.repairEventCursorIfImpossible(ctx, ts)
+}
+
+// repairEventCursorIfImpossible returns ts, or the repaired value after persisting it when ts is impossible.
+func (s *Scheduler) repairEventCursorIfImpossible(ctx context.Context, ts int64) int64 {
+	now := time.Now()
+	if !cursorIsImpossible(ts, now) {
+		return ts
+	}
+	repaired := s.repairedCursor(now)
+	slog.Error("Scheduler (Events): Event cursor is impossible, event processing would stall. Resetting it.",
+		"stored_timestamp", ts, "stored_time", time.Unix(ts, 0).UTC(), "reset_to", repaired, "lookback", s.cfg.EventCursorRepairLookback.String())
+	if err := s.modRepo.SetSchedulerLastSyncEventTimestamp(ctx, repaired); err != nil {
+		logSyncError(eventsLogPrefix, "Failed to persist repaired event cursor.", err)
+	}
+	return repaired
+}
+
+// cursorNeedsReconciliation reports whether the event cursor is older than EventCursorStaleThreshold, in
+// which case mod.io may no longer return every event after it and polling would silently miss changes.
+// An idle game's cursor stops advancing too, so it also requires that no sync cycle succeeded within the
+// threshold, i.e. that polling itself stalled (a long pause, a suspended host) rather than mod.io going quiet.
+func (s *Scheduler) cursorNeedsReconciliation(ctx context.Context, ts int64) bool {
+	threshold := s.cfg.EventCursorStaleThreshold
+	if ts <= 0 || threshold <= 0 || time.Since(time.Unix(ts, 0)) <= threshold {
+		return false
+	}
+	lastWrite, err := s.modRepo.GetLastOverallWriteTimestamp(ctx)
+	if err != nil {
+		slog.Warn("Scheduler (Events): Could not read last overall write timestamp for the stale cursor check", "error", err)
+		return false
+	}
+	return !lastWrite.IsZero() && time.Since(lastWrite) > threshold
+}
+
+// ResetEventCursor sets the event cursor to ts, or to now minus the repair lookback when ts is zero.
+// It fails with ErrSyncInProgress rather than waiting on a running sync.
+func (s *Scheduler) ResetEventCursor(ctx context.Context, ts int64) (CursorReset, error) {
+	now := time.Now()
+	if ts == 0 {
+		ts = s.repairedCursor(now)
+	}
+	if cursorIsImpossible(ts, now) {
+		return CursorReset{}, ErrInvalidCursor
+	}
+	if !s.updateMu.TryLock() {
+		return CursorReset{}, ErrSyncInProgress
+	}
+	defer s.updateMu.Unlock()
+
+	previous, err := s.modRepo.GetSchedulerLastSyncEventTimestamp(ctx)
+	if err != nil {
+		return CursorReset{}, err
+	}
+	if err := s.modRepo.SetSchedulerLastSyncEventTimestamp(ctx, ts); err != nil {
+		return CursorReset{}, err
+	}
+	slog.Warn("Scheduler (Events): Event cursor reset manually.", "previous", previous, "current", ts)
+	return CursorReset{Previous: previous, Current: ts}, nil
+}
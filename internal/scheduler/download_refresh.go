@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+)
+
+const (
+	downloadRefreshTimeout = 2 * time.Minute
+	maxDownloadRefreshIDs  = 100 // One id-in request to mod.io, see GetModDetailsByIDs
+)
+
+type expiringDownload struct {
+	modID     int
+	expiresAt int64
+}
+
+// refreshExpiringDownloads re-fetches the cached mods whose download links expire before the next tick,
+// soonest first and at most DOWNLOAD_REFRESH_BATCH_SIZE of them, so each tick costs at most one mod.io
+// request however many links expire together. Mods left over are picked up on later ticks. Request counts
+// aren't tracked, so expiry is the only priority.
+func (s *Scheduler) refreshExpiringDownloads(ctx context.Context) {
+	deadline := time.Now().Add(s.cfg.DownloadRefreshInterval).Unix()
+	var expiring []expiringDownload
+	for _, t := range s.syncTypes {
+		err := s.modRepo.ScanModsByType(ctx, t.tag, func(mod *modio.Mod) error {
+			if expiresAt := mod.Modfile.Download.DateExpires; expiresAt > 0 && expiresAt <= deadline {
+				expiring = append(expiring, expiringDownload{modID: mod.ID, expiresAt: expiresAt})
+			}
+			return nil
+		})
+		if err != nil {
+			slog.Error("Scheduler (Downloads): Failed to scan cached mods for expiring download links", "type", t.tag, "error", err)
+			return
+		}
+	}
+	if len(expiring) == 0 {
+		return
+	}
+
+	sort.Slice(expiring, func(i, j int) bool { return expiring[i].expiresAt < expiring[j].expiresAt })
+	batch := expiring[:min(len(expiring), s.cfg.DownloadRefreshBatchSize, maxDownloadRefreshIDs)]
+	modIDs := make([]int, len(batch))
+	for i, download := range batch {
+		modIDs[i] = download.modID
+	}
+
+	results, err := s.RefreshMods(ctx, modIDs)
+	if errors.Is(err, ErrSyncInProgress) {
+		slog.Info("Scheduler (Downloads): Sync in progress, deferring download link refresh.", "expiring", len(expiring))
+		return
+	}
+	if err != nil {
+		logSyncError("Scheduler (Downloads):", "Failed to refresh expiring download links.", err, "count", len(modIDs))
+		if !s.noteAuthFailure(err) {
+			s.noteRateLimited(err)
+		}
+		return
+	}
+	refreshed := 0
+	for _, result := range results {
+		if result.Status == RefreshStatusUpdated {
+			refreshed++
+		}
+	}
+	slog.Info("Scheduler (Downloads): Refreshed expiring download links.", "refreshed", refreshed, "batch", len(modIDs), "expiring", len(expiring))
+}
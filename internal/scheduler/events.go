@@ -0,0 +1,179 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+)
+
+// modDetailsSource is the part of the mod.io client processEvent uses.
+type modDetailsSource interface {
+	GetModDetails(ctx context.Context, modID int) (*modio.Mod, error)
+}
+
+// cachedModSource is the part of the repository processEvent uses.
+type cachedModSource interface {
+	GetModByID(ctx context.Context, modID int) (*modio.Mod, error)
+}
+
+// detailFetchEventTypes are the event types processEvent fetches the mod's current details for.
+var detailFetchEventTypes = map[string]bool{"MOD_AVAILABLE": true, "MOD_EDITED": true, "MODFILE_CHANGED": true}
+
+// modDetailsResult is the outcome of one prefetched GetModDetails call.
+type modDetailsResult struct {
+	mod *modio.Mod
+	err error
+}
+
+// prefetchedModDetails serves processEvent the mod details fetched ahead of a batch, so the batch's
+// requests overlap instead of running one after another. Mods it holds nothing for are fetched from
+// source then, unless the prefetch was stopped, in which case they get the error that stopped it.
+type prefetchedModDetails struct {
+	source  modDetailsSource
+	results map[int]modDetailsResult
+	stopErr error
+}
+
+func (p *prefetchedModDetails) GetModDetails(ctx context.Context, modID int) (*modio.Mod, error) {
+	if result, ok := p.results[modID]; ok {
+		return result.mod, result.err
+	}
+	if p.stopErr != nil {
+		return nil, p.stopErr
+	}
+	return p.source.GetModDetails(ctx, modID)
+}
+
+// prefetchModDetails fetches the details of every mod the events will need, once per mod and with up to
+// EventDetailFetchConcurrency requests in flight; events in skip are left out. Failures are kept per mod
+// for processEvent to handle in event order. Only mod.io rejecting the API key or rate limiting us stops
+// the fetches not yet started, as the batch is abandoned at that mod's event anyway.
+func (s *Scheduler) prefetchModDetails(ctx context.Context, events []modio.ModioEvent, skip map[int]bool) *prefetchedModDetails {
+	prefetched := &prefetchedModDetails{source: s.eventSource, results: make(map[int]modDetailsResult)}
+	var modIDs []int
+	queued := make(map[int]bool)
+	for _, event := range events {
+		if skip[event.ID] || !detailFetchEventTypes[event.EventType] || queued[event.ModID] {
+			continue
+		}
+		queued[event.ModID] = true
+		modIDs = append(modIDs, event.ModID)
+	}
+	if len(modIDs) == 0 {
+		return prefetched
+	}
+
+	started := time.Now()
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	workers := min(s.cfg.EventDetailFetchConcurrency, len(modIDs))
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for modID := range queue {
+				mod, err := s.eventSource.GetModDetails(fetchCtx, modID)
+				mu.Lock()
+				switch {
+				case errors.Is(err, modio.ErrUnauthorized) || modio.IsRateLimited(err):
+					if prefetched.stopErr == nil {
+						prefetched.stopErr = err
+						cancel()
+					}
+					prefetched.results[modID] = modDetailsResult{mod, err}
+				case fetchCtx.Err() != nil && ctx.Err() == nil:
+					// Cut short by the stop above; leave the mod to stopErr rather than a cancellation.
+				default:
+					prefetched.results[modID] = modDetailsResult{mod, err}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, modID := range modIDs {
+		select {
+		case queue <- modID:
+		case <-fetchCtx.Done():
+			break dispatch
+		case <-s.stopChan:
+			break dispatch
+		}
+	}
+	close(queue)
+	wg.Wait()
+	slog.Info("Scheduler (Events): Prefetched mod details.", "mods", len(modIDs), "fetched", len(prefetched.results),
+		"concurrency", workers, "duration", time.Since(started).Round(time.Millisecond))
+	return prefetched
+}
+
+// eventOutcome tells the caller of processEvent whether the event cursor may move past an event.
+type eventOutcome int
+
+const (
+	eventApplied     eventOutcome = iota // Commands were queued, or the event type needs none
+	eventSkippedNoop                     // The mod was unchanged since the cached copy
+	eventNotApplied                      // The mod could not be fetched; a later event or full sync reconciles it
+)
+
+// processEvent records the change for a single mod event. It is shared by event polling and webhook
+// ingestion, and reaches mod.io and Redis only through details (see prefetchModDetails) and eventCache,
+// writing nothing itself: the changes go to the caller's change set. The only errors it returns are the
+// context's, mod.io rejecting the API key and mod.io rate limiting us, in which case the batch should be
+// abandoned.
+func (s *Scheduler) processEvent(ctx context.Context, changes *repository.ChangeSet, details modDetailsSource, event modio.ModioEvent) (eventOutcome, error) {
+	slog.Debug("Scheduler (Events): Processing event", "event_id", event.ID, "mod_id", event.ModID, "type", event.EventType, "date_added", event.DateAdded)
+	oldModData, err := s.eventCache.GetModByID(ctx, event.ModID)
+	if isContextError(err) {
+		return eventNotApplied, err
+	}
+	if err != nil {
+		slog.Error("Scheduler (Events): Failed to get old mod data from repository for event processing", "mod_id", event.ModID, "event_type", event.EventType, "error", err)
+	}
+
+	switch event.EventType {
+	case "MOD_DELETED", "MOD_UNAVAILABLE":
+		if oldModData != nil {
+			slog.Info("Scheduler (Events): Mod marked for deletion from repository", "mod_id", event.ModID, "event_type", event.EventType)
+		} else {
+			slog.Warn("Scheduler (Events): Mod to be deleted/unavailable not found in repository, or type unknown. Full sync will reconcile.", "mod_id", event.ModID)
+		}
+		changes.Delete(event.ModID, "")
+	case "MOD_AVAILABLE", "MOD_EDITED", "MODFILE_CHANGED":
+		newModData, err := details.GetModDetails(ctx, event.ModID)
+		if isContextError(err) || s.noteAuthFailure(err) || s.noteRateLimited(err) {
+			return eventNotApplied, err
+		}
+		if errors.Is(err, modio.ErrNotFound) {
+			slog.Warn("Scheduler (Events): Mod details not found on Mod.io after update event, possibly became unavailable immediately.", "mod_id", event.ModID, "event_type", event.EventType)
+			changes.Delete(event.ModID, "")
+			return eventNotApplied, nil
+		}
+		if err != nil {
+			slog.Error("Scheduler (Events): Failed to fetch updated mod details from Mod.io", "mod_id", event.ModID, "event_type", event.EventType, "error", err)
+			return eventNotApplied, nil
+		}
+
+		if oldModData != nil && oldModData.DateUpdated == newModData.DateUpdated && !changes.Has(event.ModID) {
+			// mod.io has no ETags for single mods, so an unchanged date_updated is our best signal that
+			// the event was spurious and re-indexing would only rewrite identical data.
+			slog.Debug("Scheduler (Events): Mod unchanged since cached copy, skipping re-index", "mod_id", event.ModID, "event_type", event.EventType, "date_updated", newModData.DateUpdated)
+			return eventSkippedNoop, nil
+		}
+
+		changes.Upsert(newModData, "")
+		slog.Info("Scheduler (Events): Mod marked for save/update in repository", "mod_id", newModData.ID, "event_type", event.EventType)
+	default:
+		slog.Debug("Scheduler (Events): Ignoring event type", "type", event.EventType, "mod_id", event.ModID)
+	}
+	return eventApplied, nil
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const (
+	initialSyncTimeout       = 15 * time.Minute
+	initialSyncFirstRetry    = time.Minute
+	initialSyncMaxRetryDelay = 30 * time.Minute
+)
+
+// Initial sync states.
+const (
+	InitialSyncRunning   = "running"
+	InitialSyncSucceeded = "succeeded"
+	InitialSyncRetrying  = "retrying" // Failed, another attempt is scheduled at NextRetryAt
+	InitialSyncFailed    = "failed"   // Failed with no retries left; the scheduled full sync is next
+)
+
+// InitialSyncStatus reports the startup full sync and its retries.
+type InitialSyncStatus struct {
+	State           string     `json:"state"`
+	Attempts        int        `json:"attempts"`
+	StartedAt       time.Time  `json:"startedAt"`
+	FinishedAt      *time.Time `json:"finishedAt,omitempty"`
+	DurationSeconds float64    `json:"durationSeconds,omitempty"` // Of the last attempt
+	Error           string     `json:"error,omitempty"`
+	NextRetryAt     *time.Time `json:"nextRetryAt,omitempty"`
+}
+
+// InitialSyncStatus returns the startup sync's status, or nil before Start has begun it.
+func (s *Scheduler) InitialSyncStatus() *InitialSyncStatus {
+	s.initialMu.Lock()
+	defer s.initialMu.Unlock()
+	if s.initialSync == nil {
+		return nil
+	}
+	status := *s.initialSync
+	return &status
+}
+
+func (s *Scheduler) setInitialSyncStatus(update func(*InitialSyncStatus)) {
+	s.initialMu.Lock()
+	defer s.initialMu.Unlock()
+	if s.initialSync == nil {
+		s.initialSync = &InitialSyncStatus{}
+	}
+	update(s.initialSync)
+}
+
+// runInitialSync runs the startup full sync, retrying failures with doubling delays up to
+// INITIAL_SYNC_RETRIES times rather than leaving the cache empty until the next scheduled sync. Retries
+// stop once any full sync of every type has succeeded.
+func (s *Scheduler) runInitialSync(ctx context.Context) {
+	validateCtx, cancel := context.WithTimeout(ctx, time.Minute)
+	s.ValidateEventCursor(validateCtx)
+	opts := s.catchUpSyncOptions(validateCtx) // Decided once: a failed attempt still records a write
+	cancel()
+
+	delay := initialSyncFirstRetry
+	for attempt := 1; ; attempt++ {
+		startedAt := time.Now().UTC()
+		s.setInitialSyncStatus(func(status *InitialSyncStatus) {
+			if attempt == 1 {
+				status.StartedAt = startedAt
+			}
+			status.State, status.Attempts, status.NextRetryAt = InitialSyncRunning, attempt, nil
+		})
+
+		attemptCtx, attemptCancel := context.WithTimeout(ctx, initialSyncTimeout)
+		err := s.runFullSynchronization(attemptCtx, opts)
+		attemptCancel()
+		finishedAt := time.Now().UTC()
+
+		if err == nil || s.HasCompletedFullSync() {
+			slog.Info("Scheduler: Initial full sync succeeded.", "attempts", attempt, "duration", finishedAt.Sub(startedAt).Round(time.Millisecond).String())
+			s.setInitialSyncStatus(func(status *InitialSyncStatus) {
+				status.State, status.FinishedAt, status.Error = InitialSyncSucceeded, &finishedAt, ""
+				status.DurationSeconds = finishedAt.Sub(startedAt).Seconds()
+			})
+			return
+		}
+
+		retry := attempt <= s.cfg.InitialSyncRetries && ctx.Err() == nil
+		nextRetryAt := finishedAt.Add(delay)
+		s.setInitialSyncStatus(func(status *InitialSyncStatus) {
+			status.FinishedAt, status.Error = &finishedAt, err.Error()
+			status.DurationSeconds = finishedAt.Sub(startedAt).Seconds()
+			if retry {
+				status.State, status.NextRetryAt = InitialSyncRetrying, &nextRetryAt
+			} else {
+				status.State = InitialSyncFailed
+			}
+		})
+		if !retry {
+			logSyncError(fullSyncLogPrefix, "Initial full sync failed, waiting for the scheduled full sync.", err, "attempts", attempt)
+			return
+		}
+		logSyncError(fullSyncLogPrefix, "Initial full sync failed, retrying.", err, "attempt", attempt, "retry_in", delay.String())
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		if s.HasCompletedFullSync() {
+			// A scheduled or admin sync got there first.
+			s.setInitialSyncStatus(func(status *InitialSyncStatus) {
+				status.State, status.NextRetryAt, status.Error = InitialSyncSucceeded, nil, ""
+			})
+			return
+		}
+		opts.triggeredBy = "initial_startup_retry"
+		delay = min(delay*2, initialSyncMaxRetryDelay)
+	}
+}
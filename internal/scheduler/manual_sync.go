@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+const manualSyncTimeout = 30 * time.Minute
+
+// TriggerFullSync queues a full sync of every type through the admin queue, see QueueFullSync, so it waits
+// behind a running one rather than being skipped. It returns ErrSyncQueueFull while one runs and another
+// waits, and ctx's error if the caller has already gone.
+func (s *Scheduler) TriggerFullSync(ctx context.Context) (SyncQueueState, error) {
+	if err := ctx.Err(); err != nil {
+		return SyncQueueState{}, err
+	}
+	return s.QueueFullSync("")
+}
+
+// TriggerEventSync starts an event processing cycle in the background at once, without the debounce of
+// RequestEventSync, returning ErrSyncInProgress if another sync holds the update lock.
+func (s *Scheduler) TriggerEventSync(ctx context.Context) error {
+	return s.startManualSync(ctx, func(ctx context.Context) {
+		s.eventCycleLocked(ctx, "admin_event_sync")
+	})
+}
+
+func (s *Scheduler) startManualSync(ctx context.Context, run func(context.Context)) error {
+	if !s.updateMu.TryLock() {
+		return ErrSyncInProgress
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), manualSyncTimeout)
+	stop := context.AfterFunc(s.baseContext(), cancel)
+	go func() {
+		defer s.updateMu.Unlock()
+		defer cancel()
+		defer stop()
+		run(ctx)
+	}()
+	return nil
+}
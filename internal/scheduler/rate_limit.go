@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+)
+
+const defaultRateLimitPause = time.Minute // When a 429 carries no Retry-After
+
+// noteRateLimited pauses scheduled syncs for mod.io's Retry-After if err is a rate-limit error, and
+// reports whether it was. The client has already retried the request by then, so pressing on with the
+// rest of a batch would only collect more 429s.
+func (s *Scheduler) noteRateLimited(err error) bool {
+	if !modio.IsRateLimited(err) {
+		return false
+	}
+	pause, ok := modio.RetryAfter(err)
+	if !ok {
+		pause = defaultRateLimitPause
+	}
+	until := time.Now().Add(pause)
+	s.rateLimitedUntil.Store(until.UnixNano())
+	slog.Warn("Scheduler: mod.io rate limit exceeded, stopping the sync and pausing scheduled syncs.",
+		"retry_at", until.UTC().Format(time.RFC3339), "error", err)
+	return true
+}
+
+// inRateLimitPause reports whether a scheduled sync should be skipped because mod.io recently answered 429.
+func (s *Scheduler) inRateLimitPause(triggeredBy string) bool {
+	until := time.Unix(0, s.rateLimitedUntil.Load())
+	if !time.Now().Before(until) {
+		return false
+	}
+	slog.Warn("Scheduler: Skipping sync while mod.io is rate limiting us.", "triggered_by", triggeredBy, "retry_at", until.UTC().Format(time.RFC3339))
+	return true
+}
+
+// pausedForModio reports whether scheduled work should wait, for an auth failure or a rate limit.
+func (s *Scheduler) pausedForModio(triggeredBy string) bool {
+	return s.inAuthCooldown(triggeredBy) || s.inRateLimitPause(triggeredBy)
+}
@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+)
+
+const (
+	RefreshStatusUpdated  = "updated"
+	RefreshStatusDeleted  = "deleted"   // Gone from mod.io, removed from the cache
+	RefreshStatusNotFound = "not_found" // Neither on mod.io nor cached
+	RefreshStatusFailed   = "failed"
+)
+
+// RefreshResult is the outcome of a targeted refresh for one mod.
+type RefreshResult struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// RefreshMods re-fetches the given mods from mod.io and re-indexes them, removing those mod.io no longer
+// returns. Returns ErrSyncInProgress if a sync holds the update lock.
+func (s *Scheduler) RefreshMods(ctx context.Context, modIDs []int) ([]RefreshResult, error) {
+	if !s.updateMu.TryLock() {
+		return nil, ErrSyncInProgress
+	}
+	defer s.updateMu.Unlock()
+	slog.Info("Scheduler (Refresh): Starting targeted refresh.", "count", len(modIDs))
+
+	liveMods, err := s.modioClient.GetModDetailsByIDs(ctx, modIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch mods for refresh: %w", err)
+	}
+
+	changes := &repository.ChangeSet{}
+	for _, modID := range modIDs {
+		if liveMod := liveMods[modID]; liveMod != nil {
+			changes.Upsert(liveMod, "")
+		} else {
+			changes.Delete(modID, "")
+		}
+	}
+	summary, err := s.modRepo.ApplyChanges(ctx, changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply refreshed mods: %w", err)
+	}
+
+	results := make([]RefreshResult, 0, len(modIDs))
+	for _, modID := range modIDs {
+		switch {
+		case slices.Contains(summary.UpsertedIDs, modID):
+			results = append(results, RefreshResult{ID: modID, Status: RefreshStatusUpdated})
+		case liveMods[modID] != nil:
+			results = append(results, RefreshResult{ID: modID, Status: RefreshStatusFailed, Error: "failed to store mod"})
+		case slices.Contains(summary.DeletedIDs, modID):
+			results = append(results, RefreshResult{ID: modID, Status: RefreshStatusDeleted})
+		default:
+			results = append(results, RefreshResult{ID: modID, Status: RefreshStatusNotFound})
+		}
+	}
+
+	if len(summary.UpsertedIDs) > 0 || len(summary.DeletedIDs) > 0 {
+		if err := s.modRepo.SetLastOverallWriteTimestamp(ctx, time.Now().UTC()); err != nil {
+			slog.Error("Scheduler (Refresh): Failed to update last overall write timestamp.", "error", err)
+		}
+		s.bumpSyncGeneration(ctx, "refresh", summary)
+	}
+	slog.Info("Scheduler (Refresh): Targeted refresh finished.", "count", len(modIDs), "found_on_modio", len(liveMods))
+	return results, nil
+}
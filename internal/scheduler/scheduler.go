@@ -2,29 +2,99 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ShawnEdgell/modio-api-go/internal/config"
+	"github.com/ShawnEdgell/modio-api-go/internal/metrics"
 	"github.com/ShawnEdgell/modio-api-go/internal/modio"
 	"github.com/ShawnEdgell/modio-api-go/internal/repository" // Ensure this path is correct
 )
 
 const (
-	modEventsPageLimit       = 100
-	mapPageCountSafeguard    = 25
-	scriptPageCountSafeguard = 15
+	modEventsPageLimit        = 100
+	maxEventPagesPerCycle     = 20 // Absolute cap on event pages per cycle, whatever mod.io reports
+	defaultPageCountSafeguard = 25
 )
 
+// pageCountSafeguards overrides defaultPageCountSafeguard for types known to need fewer pages.
+var pageCountSafeguards = map[string]int{"script": 15}
+
+const progressBufferSize = 16
+
+const (
+	eventsLogPrefix   = "Scheduler (Events):"
+	fullSyncLogPrefix = "Scheduler (Full Sync):"
+)
+
+// ErrSyncInProgress is returned when a sync can't start because another one holds the update lock.
+var ErrSyncInProgress = errors.New("a sync is already in progress")
+
+// ErrAuthCooldown is returned when a scheduled sync is skipped because mod.io recently rejected the API key
+// or rate limited us.
+var ErrAuthCooldown = errors.New("sync skipped while the mod.io API key is failing or rate limited")
+
+const (
+	SyncStageFetching      = "fetching"
+	SyncStageIndexing      = "indexing"
+	SyncStageTypeCompleted = "type_completed"
+	SyncStageCompleted     = "completed"
+	SyncStageFailed        = "failed"
+)
+
+// SyncProgress is a point-in-time progress report for a running full sync.
+type SyncProgress struct {
+	Stage              string `json:"stage"`
+	Type               string `json:"type,omitempty"`
+	PagesFetched       int    `json:"pagesFetched,omitempty"`
+	ModsFetched        int    `json:"modsFetched,omitempty"`
+	ModsProcessed      int    `json:"modsProcessed,omitempty"`
+	TotalMods          int    `json:"totalMods,omitempty"`
+	EstimatedRemaining int    `json:"estimatedRemaining"` // Mods still to fetch or index for the current type
+	Error              string `json:"error,omitempty"`
+}
+
 type Scheduler struct {
 	modioClient *modio.Client
 	modRepo     *repository.ModRepository
 	cfg         *config.AppConfig
+	eventSource modDetailsSource // modioClient, behind an interface for processEvent
+	eventCache  cachedModSource  // modRepo, behind an interface for processEvent
 	stopChan    chan struct{}
 	updateMu    sync.Mutex
+
+	skippedNoopUpdates atomic.Int64 // Edit events whose fetched mod matched the cached DateUpdated
+	fullSyncCompleted  atomic.Bool  // Set once a full sync of every type has succeeded in this process
+	warmingCaches      atomic.Bool  // Set while warmCaches requests are in flight
+
+	webhookEvents chan modio.ModioEvent // Pushed events awaiting runWebhookWorker
+	eventSyncReqs chan struct{}         // On-demand event sync triggers, see RequestEventSync
+
+	baseCtx context.Context // Set by Start and cancelled by Stop
+
+	queueMu     sync.Mutex // Guards the admin full sync queue, see QueueFullSync
+	runningSync *QueuedSync
+	queuedSync  *QueuedSync
+
+	authMu      sync.Mutex // Guards authFailure
+	authFailure *AuthFailure
+
+	rateLimitedUntil atomic.Int64 // Unix nanoseconds until which scheduled syncs wait, see noteRateLimited
+
+	initialMu   sync.Mutex // Guards initialSync
+	initialSync *InitialSyncStatus
+
+	syncTypes []syncType // One per configured category, in config order
+
+	auditCursor uint64     // Blob scan position of the next integrity audit; only the ticker goroutine uses it
+	auditMu     sync.Mutex // Guards lastAudit
+	lastAudit   *IntegrityAuditResult
 }
 
 func NewScheduler(client *modio.Client, repo *repository.ModRepository, cfg *config.AppConfig) *Scheduler {
@@ -32,21 +102,53 @@ func NewScheduler(client *modio.Client, repo *repository.ModRepository, cfg *con
 		modioClient: client,
 		modRepo:     repo,
 		cfg:         cfg,
+		eventSource: client,
+		eventCache:  repo,
 		stopChan:    make(chan struct{}),
+
+		webhookEvents: make(chan modio.ModioEvent, webhookQueueSize),
+		eventSyncReqs: make(chan struct{}, 1),
+
+		syncTypes: newSyncTypes(cfg.ModCategories),
 	}
 }
 
 func (s *Scheduler) processRecentChangesViaEvents(ctx context.Context, triggeredBy string) {
+	if s.pausedForModio(triggeredBy) {
+		return
+	}
 	if !s.updateMu.TryLock() {
 		slog.Info("Scheduler: Event processing or full sync already in progress, skipping.", "triggered_by", triggeredBy)
 		return
 	}
-	slog.Info("Scheduler: Starting event processing cycle.", "triggered_by", triggeredBy)
 	defer s.updateMu.Unlock()
+	s.eventCycleLocked(ctx, triggeredBy)
+}
+
+// eventCycleLocked runs one event processing cycle. The caller must hold updateMu.
+func (s *Scheduler) eventCycleLocked(ctx context.Context, triggeredBy string) {
+	defer metrics.ObserveEventSync(time.Now())
+	slog.Info("Scheduler: Starting event processing cycle.", "triggered_by", triggeredBy)
 
 	lastSyncEventTs, err := s.modRepo.GetSchedulerLastSyncEventTimestamp(ctx)
 	if err != nil {
-		slog.Error("Scheduler (Events): Failed to get last sync event timestamp from repository. Aborting event processing.", "error", err)
+		logSyncError(eventsLogPrefix, "Failed to get last sync event timestamp from repository. Aborting event processing.", err)
+		return
+	}
+	lastSyncEventTs = s.repairEventCursorIfImpossible(ctx, lastSyncEventTs)
+	if s.cursorNeedsReconciliation(ctx, lastSyncEventTs) {
+		slog.Warn("Scheduler (Events): Event cursor is older than the stale threshold, running a reconciliation full sync instead of event processing.",
+			"cursor", lastSyncEventTs, "cursor_time", time.Unix(lastSyncEventTs, 0).UTC(), "threshold", s.cfg.EventCursorStaleThreshold.String(), "triggered_by", triggeredBy)
+		// The event cursor is kept, as after a catch-up sync (see catchUpSyncOptions): the page safeguards
+		// may leave older edited mods out of the snapshot, and polling from the old cursor replays whatever
+		// events mod.io still returns for them.
+		syncCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), reconciliationSyncTimeout)
+		defer cancel()
+		stop := context.AfterFunc(s.baseContext(), cancel) // Still stopped by shutdown
+		defer stop()
+		if err := s.fullSyncLocked(syncCtx, fullSyncOptions{triggeredBy: "stale_event_cursor", preserveEventCursor: true}); err != nil {
+			logSyncError(eventsLogPrefix, "Reconciliation full sync failed.", err)
+		}
 		return
 	}
 	if lastSyncEventTs == 0 {
@@ -60,18 +162,48 @@ func (s *Scheduler) processRecentChangesViaEvents(ctx context.Context, triggered
 	maxEventsToProcessInOneCycle := 1000
 	totalEventsFetchedThisCycle := 0
 
-	for {
+	fetchedEventIDs := make(map[int]bool)
+	var lastPageNewestTs int64
+	for page := 0; ; page++ {
+		if page >= maxEventPagesPerCycle {
+			slog.Error("Scheduler (Events): Event page cap reached, processing what was fetched.", "pages", page, "events", totalEventsFetchedThisCycle)
+			break
+		}
 		eventsResponse, err := s.modioClient.FetchModEvents(ctx, lastSyncEventTs, currentOffset, modEventsPageLimit)
 		if err != nil {
+			if isContextError(err) {
+				// Don't process a partial batch: the cursor would advance past events we never fetched.
+				logSyncError(eventsLogPrefix, "Failed to fetch mod events page from Mod.io", err, "offset", currentOffset)
+				return
+			}
+			if s.noteAuthFailure(err) || s.noteRateLimited(err) {
+				return
+			}
 			slog.Error("Scheduler (Events): Failed to fetch mod events page from Mod.io", "offset", currentOffset, "error", err)
 			break
 		}
+		s.ClearAuthFailure()
 
 		if eventsResponse == nil || len(eventsResponse.Data) == 0 {
 			slog.Info("Scheduler (Events): No more new events found.", "total_fetched_this_page", 0)
 			break
 		}
 		slog.Info("Scheduler (Events): Fetched events page.", "count", len(eventsResponse.Data), "offset", currentOffset, "total_available", eventsResponse.ResultTotal)
+		newEvents := 0
+		for _, event := range eventsResponse.Data {
+			if event.ID == 0 || !fetchedEventIDs[event.ID] {
+				newEvents++
+				fetchedEventIDs[event.ID] = true
+			}
+		}
+		pageNewestTs := eventsResponse.Data[len(eventsResponse.Data)-1].DateAdded // Pages are sorted by date_added
+		if newEvents == 0 || pageNewestTs < lastPageNewestTs {
+			// A repeated or backwards page means mod.io's paging is inconsistent; more pages would not help.
+			slog.Error("Scheduler (Events): Event pagination made no progress, processing what was fetched.",
+				"offset", currentOffset, "new_events", newEvents, "page_newest_ts", pageNewestTs, "previous_page_newest_ts", lastPageNewestTs)
+			break
+		}
+		lastPageNewestTs = pageNewestTs
 		allEventsToProcess = append(allEventsToProcess, eventsResponse.Data...)
 		totalEventsFetchedThisCycle += len(eventsResponse.Data)
 
@@ -79,10 +211,10 @@ func (s *Scheduler) processRecentChangesViaEvents(ctx context.Context, triggered
 			break
 		}
 		currentOffset += len(eventsResponse.Data)
-		
+
 		select {
 		case <-ctx.Done():
-			slog.Info("Scheduler (Events): Context cancelled during event pagination.")
+			logSyncError(eventsLogPrefix, "Stopped during event pagination.", ctx.Err())
 			return
 		case <-s.stopChan:
 			slog.Info("Scheduler (Events): Stop signal received during event pagination.")
@@ -100,13 +232,16 @@ func (s *Scheduler) processRecentChangesViaEvents(ctx context.Context, triggered
 	}
 
 	slog.Info("Scheduler (Events): Processing events.", "count", len(allEventsToProcess))
-	pipe := s.modRepo.Client().Pipeline() // Corrected: Use Client() method to get *redis.Client, then Pipeline()
+	changes := &repository.ChangeSet{}
 	var latestEventTsProcessedInBatch int64 = lastSyncEventTs
+	skippedNoopUpdates := 0
+	seenEventIDs := s.findProcessedEventIDs(ctx, allEventsToProcess)
+	details := s.prefetchModDetails(ctx, allEventsToProcess, seenEventIDs)
 
 	for _, event := range allEventsToProcess {
 		select {
 		case <-ctx.Done():
-			slog.Info("Scheduler (Events): Context cancelled during event processing loop.")
+			logSyncError(eventsLogPrefix, "Stopped during event processing loop.", ctx.Err())
 			return
 		case <-s.stopChan:
 			slog.Info("Scheduler (Events): Stop signal received during event processing loop.")
@@ -114,90 +249,23 @@ func (s *Scheduler) processRecentChangesViaEvents(ctx context.Context, triggered
 		default:
 		}
 
-		slog.Debug("Scheduler (Events): Processing event", "event_id", event.ID, "mod_id", event.ModID, "type", event.EventType, "date_added", event.DateAdded)
-		modTypeTag := ""
-
-		oldModData, err := s.modRepo.GetModByID(ctx, event.ModID)
-		if err != nil {
-			slog.Error("Scheduler (Events): Failed to get old mod data from repository for event processing", "mod_id", event.ModID, "event_type", event.EventType, "error", err)
-		}
-		if oldModData != nil {
-			isMap := false
-			isScript := false
-			for _, tag := range oldModData.Tags {
-				if tag.Name == modio.MapTag {
-					isMap = true
-					break
-				}
-				if tag.Name == modio.ScriptModTag {
-					isScript = true
-					break
-				}
-			}
-			if isMap {
-				modTypeTag = modio.MapTag
-			} else if isScript {
-				modTypeTag = modio.ScriptModTag
-			}
-		}
-
-		switch event.EventType {
-		case "MOD_DELETED", "MOD_UNAVAILABLE":
-			if oldModData != nil {
-				s.modRepo.AddRemoveModCommandsFromPipeline(ctx, pipe, oldModData, modTypeTag)
-				slog.Info("Scheduler (Events): Mod marked for deletion from repository", "mod_id", event.ModID, "event_type", event.EventType)
-			} else {
-				slog.Warn("Scheduler (Events): Mod to be deleted/unavailable not found in repository, or type unknown. Full sync will reconcile.", "mod_id", event.ModID)
-				pipe.Del(ctx, repository.ModKeyPrefix+strconv.Itoa(event.ModID)) // Corrected: Use exported ModKeyPrefix
-			}
-		case "MOD_AVAILABLE", "MOD_EDITED", "MODFILE_CHANGED":
-			newModData, err := s.modioClient.GetModDetails(ctx, event.ModID)
+		if seenEventIDs[event.ID] {
+			slog.Debug("Scheduler (Events): Skipping event processed within the dedup window", "event_id", event.ID, "mod_id", event.ModID)
+		} else {
+			outcome, err := s.processEvent(ctx, changes, details, event)
 			if err != nil {
-				slog.Error("Scheduler (Events): Failed to fetch updated mod details from Mod.io", "mod_id", event.ModID, "event_type", event.EventType, "error", err)
-				continue
+				logSyncError(eventsLogPrefix, "Stopped while processing event.", err, "mod_id", event.ModID, "event_type", event.EventType)
+				return
 			}
-			if newModData == nil {
-				slog.Warn("Scheduler (Events): Mod details not found on Mod.io after update event, possibly became unavailable immediately.", "mod_id", event.ModID, "event_type", event.EventType)
-				if oldModData != nil {
-					s.modRepo.AddRemoveModCommandsFromPipeline(ctx, pipe, oldModData, modTypeTag)
-				} else {
-					pipe.Del(ctx, repository.ModKeyPrefix+strconv.Itoa(event.ModID)) // Corrected: Use exported ModKeyPrefix
-				}
+			if outcome == eventNotApplied {
 				continue
 			}
-			
-			isMapNew := false
-			isScriptNew := false
-			for _, tag := range newModData.Tags {
-				if tag.Name == modio.MapTag {
-					isMapNew = true; break
-				}
-				if tag.Name == modio.ScriptModTag {
-					isScriptNew = true; break
-				}
-			}
-			if isMapNew { modTypeTag = modio.MapTag } else if isScriptNew { modTypeTag = modio.ScriptModTag } else {
-				// If type cannot be determined from new tags, try to use old type if available
-				if modTypeTag == "" && oldModData != nil {
-					// modTypeTag would have been set from oldModData's tags
-				} else if modTypeTag == "" {
-					slog.Warn("Scheduler (Events): Could not determine mod type for new/updated mod, tag indexing may be incomplete", "mod_id", newModData.ID)
-					// Default to a generic or no type for indexing if necessary, or skip type-specific indexes
-				}
-			}
-
-
-			if oldModData != nil {
-				s.modRepo.RemoveOrphanedTagIndexEntries(ctx, pipe, oldModData, newModData, modTypeTag)
+			if outcome == eventSkippedNoop {
+				skippedNoopUpdates++
 			}
-			err = s.modRepo.AddModCommandsToPipeline(ctx, pipe, newModData, modTypeTag)
-			if err != nil {
-				slog.Error("Scheduler (Events): Error adding save commands to pipeline for mod", "mod_id", newModData.ID, "error", err)
-			} else {
-				slog.Info("Scheduler (Events): Mod marked for save/update in repository", "mod_id", newModData.ID, "event_type", event.EventType)
+			if event.ID != 0 {
+				changes.MarkEventsProcessed(s.cfg.EventDedupWindow, event.ID)
 			}
-		default:
-			slog.Debug("Scheduler (Events): Ignoring event type", "type", event.EventType, "mod_id", event.ModID)
 		}
 
 		if event.DateAdded > latestEventTsProcessedInBatch {
@@ -205,14 +273,13 @@ func (s *Scheduler) processRecentChangesViaEvents(ctx context.Context, triggered
 		}
 	}
 
-	if pipe.Len() > 0 { // Only execute if there are commands
-		if _, err := pipe.Exec(ctx); err != nil {
-			slog.Error("Scheduler (Events): Failed to execute Redis pipeline for event processing", "error", err)
-			return
-		}
+	wroteChanges := changes.Len() > 0
+	summary, err := s.modRepo.ApplyChanges(ctx, changes)
+	if err != nil {
+		logSyncError(eventsLogPrefix, "Failed to apply changes for event processing", err)
+		return
 	}
 
-
 	if latestEventTsProcessedInBatch > lastSyncEventTs {
 		if err := s.modRepo.SetSchedulerLastSyncEventTimestamp(ctx, latestEventTsProcessedInBatch); err != nil {
 			slog.Error("Scheduler (Events): Failed to update last sync event timestamp in repository", "error", err)
@@ -223,127 +290,397 @@ func (s *Scheduler) processRecentChangesViaEvents(ctx context.Context, triggered
 	if err := s.modRepo.SetLastOverallWriteTimestamp(ctx, time.Now().UTC()); err != nil {
 		slog.Error("Scheduler (Events): Failed to update last overall write timestamp", "error", err)
 	}
-	slog.Info("Scheduler (Events): Event processing cycle finished.")
+	if wroteChanges {
+		s.bumpSyncGeneration(ctx, "events", summary)
+	}
+	if skippedNoopUpdates > 0 {
+		s.skippedNoopUpdates.Add(int64(skippedNoopUpdates))
+	}
+	slog.Info("Scheduler (Events): Event processing cycle finished.", "skipped_noop_updates", skippedNoopUpdates, "skipped_noop_updates_total", s.skippedNoopUpdates.Load())
+	s.logRateLimit(eventsLogPrefix)
 }
 
-func (s *Scheduler) runFullSynchronization(ctx context.Context, triggeredBy string) {
+// findProcessedEventIDs looks up which events were already processed within the dedup window, so
+// overlapping cursors or a webhook that beat polling don't refetch the same mods. Lookup failures only
+// cost the dedup, never the events.
+func (s *Scheduler) findProcessedEventIDs(ctx context.Context, events []modio.ModioEvent) map[int]bool {
+	eventIDs := make([]int, 0, len(events))
+	for _, event := range events {
+		if event.ID != 0 {
+			eventIDs = append(eventIDs, event.ID)
+		}
+	}
+	seen, err := s.modRepo.FindProcessedEventIDs(ctx, eventIDs, s.cfg.EventDedupWindow)
+	if err != nil {
+		slog.Warn("Scheduler (Events): Failed to look up processed event ids, processing all events", "error", err)
+		return map[int]bool{}
+	}
+	if len(seen) > 0 {
+		slog.Info("Scheduler (Events): Skipping events already processed within the dedup window", "count", len(seen))
+	}
+	return seen
+}
+
+// fullSyncOptions tunes a single full synchronization run.
+type fullSyncOptions struct {
+	triggeredBy string
+	progress    chan<- SyncProgress // Optional progress reports, see StreamFullSync
+
+	// preserveEventCursor keeps the stored event cursor instead of advancing it to the newest DateUpdated
+	// seen. See catchUpSyncOptions for why that matters after a long downtime.
+	preserveEventCursor bool
+
+	// typeTag limits the sync to one type. Such a sync never advances the event cursor, since the other
+	// type's pending events would be skipped.
+	typeTag string
+}
+
+// syncType is a type covered by full syncs, with the page safeguard bounding its fetch.
+type syncType struct {
+	tag           string
+	pageSafeguard int
+}
+
+func newSyncTypes(categories []string) []syncType {
+	types := make([]syncType, 0, len(categories))
+	for _, category := range categories {
+		pageSafeguard, ok := pageCountSafeguards[repository.GetModTypeFromTag(category)]
+		if !ok {
+			pageSafeguard = defaultPageCountSafeguard
+		}
+		types = append(types, syncType{tag: category, pageSafeguard: pageSafeguard})
+	}
+	return types
+}
+
+// ErrUnknownType is returned for a sync type that isn't a configured category.
+var ErrUnknownType = errors.New("unknown mod type")
+
+// ParseSyncType maps a type parameter (a type name such as "map" or the mod.io tag, case-insensitive) to
+// its configured category tag.
+func (s *Scheduler) ParseSyncType(value string) (string, error) {
+	for _, t := range s.syncTypes {
+		if strings.EqualFold(value, t.tag) || strings.EqualFold(value, repository.GetModTypeFromTag(t.tag)) {
+			return t.tag, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownType, value)
+}
+
+// runFullSynchronization runs a full sync unless another sync holds the lock or the auth cooldown applies,
+// returning ErrSyncInProgress or ErrAuthCooldown then.
+func (s *Scheduler) runFullSynchronization(ctx context.Context, opts fullSyncOptions) error {
+	if s.pausedForModio(opts.triggeredBy) {
+		return ErrAuthCooldown
+	}
 	if !s.updateMu.TryLock() {
-		slog.Info("Scheduler: Full sync or event processing already in progress, skipping.", "triggered_by", triggeredBy)
-		return
+		slog.Info("Scheduler: Full sync or event processing already in progress, skipping.", "triggered_by", opts.triggeredBy)
+		return ErrSyncInProgress
 	}
-	slog.Info("Scheduler (Full Sync): Starting full data synchronization.", "triggered_by", triggeredBy)
 	defer s.updateMu.Unlock()
+	return s.fullSyncLocked(ctx, opts)
+}
+
+// catchUpSyncOptions decides how the startup full sync treats the event cursor.
+//
+// Timestamp semantics: the event cursor (schedulerLastSyncEventTimestamp) is the date_added of the newest
+// mod.io event we have applied; event polling asks for events after it. A normal full sync advances the
+// cursor to the newest DateUpdated it saw, because its snapshot already reflects everything up to then.
+// That only holds for mods the snapshot actually covers: the per-type page safeguards cap how many mods a
+// full sync fetches, so older mods edited during a long downtime would have their events skipped forever.
+// When the last write (made by every successful sync cycle, even an idle one) is older than
+// CatchUpThreshold we therefore keep the old cursor, letting event polling replay the gap idempotently.
+func (s *Scheduler) catchUpSyncOptions(ctx context.Context) fullSyncOptions {
+	opts := fullSyncOptions{triggeredBy: "initial_startup"}
+	lastWrite, err := s.modRepo.GetLastOverallWriteTimestamp(ctx)
+	if err != nil {
+		slog.Warn("Scheduler: Could not read last overall write timestamp for catch-up check", "error", err)
+		return opts
+	}
+	if lastWrite.IsZero() || s.cfg.CatchUpThreshold <= 0 {
+		return opts
+	}
+	if downtime := time.Since(lastWrite); downtime > s.cfg.CatchUpThreshold {
+		slog.Warn("Scheduler: Long downtime detected, running catch-up full sync and preserving the event cursor",
+			"last_write", lastWrite.Format(time.RFC3339), "downtime", downtime.Round(time.Minute).String(), "threshold", s.cfg.CatchUpThreshold.String())
+		opts.triggeredBy = "catch_up_after_downtime"
+		opts.preserveEventCursor = true
+	}
+	return opts
+}
+
+// StreamFullSync starts a full sync in the background and reports its progress on the returned channel,
+// which is closed once the sync finishes. A non-empty typeTag syncs only that type. Cancelling ctx aborts
+// the sync. Returns ErrSyncInProgress without starting anything if another sync holds the lock.
+func (s *Scheduler) StreamFullSync(ctx context.Context, triggeredBy string, typeTag string) (<-chan SyncProgress, error) {
+	if !s.updateMu.TryLock() {
+		return nil, ErrSyncInProgress
+	}
+	progress := make(chan SyncProgress, progressBufferSize)
+	go func() {
+		defer close(progress)
+		defer s.updateMu.Unlock()
+		s.fullSyncLocked(ctx, fullSyncOptions{triggeredBy: triggeredBy, progress: progress, typeTag: typeTag})
+	}()
+	return progress, nil
+}
+
+// TriggerFullSyncForType runs a full sync of a single type (e.g. "map") and waits for it.
+// Returns ErrSyncInProgress if another sync holds the lock.
+func (s *Scheduler) TriggerFullSyncForType(ctx context.Context, modType string) error {
+	typeTag, err := s.ParseSyncType(modType)
+	if err != nil {
+		return err
+	}
+	if !s.updateMu.TryLock() {
+		return ErrSyncInProgress
+	}
+	defer s.updateMu.Unlock()
+	return s.fullSyncLocked(ctx, fullSyncOptions{triggeredBy: "type_sync", typeTag: typeTag})
+}
+
+// fullSyncLocked runs a full synchronization. The caller must hold updateMu.
+func (s *Scheduler) fullSyncLocked(ctx context.Context, opts fullSyncOptions) (err error) {
+	defer func(start time.Time) { metrics.ObserveFullSync(start, err) }(time.Now())
+	slog.Info("Scheduler (Full Sync): Starting full data synchronization.", "triggered_by", opts.triggeredBy, "type", opts.typeTag)
+	progress := opts.progress
+	var syncSummary repository.ChangeSummary // Across types, so the generation records every write
 
 	processType := func(itemTypeTag string, pageSafeguard int) (int64, error) { // Return max timestamp for this type
 		slog.Info("Scheduler (Full Sync): Fetching all items from Mod.io.", "type", itemTypeTag)
-		modsFromAPI, err := s.modioClient.FetchAllItems(ctx, itemTypeTag, pageSafeguard)
+		s.reportProgress(ctx, progress, SyncProgress{Stage: SyncStageFetching, Type: itemTypeTag})
+		modioTotal := -1
+		modsFromAPI, err := s.modioClient.FetchAllItemsWithProgress(ctx, itemTypeTag, pageSafeguard, func(p modio.PageProgress) {
+			modioTotal = p.ResultTotal
+			remaining := p.ResultTotal - p.ItemsFetched
+			if remaining < 0 {
+				remaining = 0
+			}
+			s.reportProgress(ctx, progress, SyncProgress{
+				Stage:              SyncStageFetching,
+				Type:               itemTypeTag,
+				PagesFetched:       p.Page,
+				ModsFetched:        p.ItemsFetched,
+				TotalMods:          p.ResultTotal,
+				EstimatedRemaining: remaining,
+			})
+		})
 		if err != nil {
 			return 0, fmt.Errorf("failed to fetch all %s from Mod.io: %w", itemTypeTag, err)
 		}
 		slog.Info("Scheduler (Full Sync): Successfully fetched items from Mod.io.", "type", itemTypeTag, "count", len(modsFromAPI))
+		metrics.ModsFetched.WithLabelValues(repository.GetModTypeFromTag(itemTypeTag)).Set(float64(len(modsFromAPI)))
+		s.logRateLimit(fullSyncLogPrefix)
 
 		modType := repository.GetModTypeFromTag(itemTypeTag) // Corrected: Use exported GetModTypeFromTag
-		idsInRepo, err := s.modRepo.GetAllModIDsByType(ctx, modType)
-		if err != nil {
-			return 0, fmt.Errorf("failed to get all %s IDs from repository: %w", modType, err)
-		}
-		slog.Debug("Scheduler (Full Sync): Current IDs in repository.", "type", modType, "count", len(idsInRepo))
-
-		apiModIDs := make(map[string]bool)
+		apiModIDs := make([]string, 0, len(modsFromAPI))
 		for i := range modsFromAPI {
-			mod := &modsFromAPI[i]
-			apiModIDs[strconv.Itoa(mod.ID)] = true
+			apiModIDs = append(apiModIDs, strconv.Itoa(modsFromAPI[i].ID))
+		}
+		staleIDs, err := s.modRepo.FindStaleModIDs(ctx, itemTypeTag, apiModIDs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to find stale %s IDs in repository: %w", modType, err)
 		}
+		slog.Debug("Scheduler (Full Sync): Stale IDs in repository.", "type", modType, "count", len(staleIDs))
 
-		pipe := s.modRepo.Client().Pipeline() // Corrected: Use Client() method
+		changes := &repository.ChangeSet{}
 		var maxModUpdateTimestampForThisType int64 = 0
 
-		for _, idInRepoStr := range idsInRepo {
-			if !apiModIDs[idInRepoStr] {
-				modID, _ := strconv.Atoi(idInRepoStr)
-				slog.Debug("Scheduler (Full Sync): Mod found in repository but not in API fetch, marking for deletion.", "type", modType, "mod_id", modID)
-				oldModData, err := s.modRepo.GetModByID(ctx, modID)
-				if err != nil {
-					slog.Error("Scheduler (Full Sync): Failed to get old mod data for deletion.", "mod_id", modID, "error", err)
-					pipe.Del(ctx, repository.ModKeyPrefix+idInRepoStr) // Corrected: Use exported ModKeyPrefix
-					continue
-				}
-				if oldModData != nil {
-					s.modRepo.AddRemoveModCommandsFromPipeline(ctx, pipe, oldModData, itemTypeTag)
-				} else {
-					pipe.Del(ctx, repository.ModKeyPrefix+idInRepoStr) // Corrected: Use exported ModKeyPrefix
-				}
+		for _, staleIDStr := range staleIDs {
+			slog.Debug("Scheduler (Full Sync): Mod found in repository but not in API fetch, marking for deletion.", "type", modType, "mod_id", staleIDStr)
+			staleID, err := strconv.Atoi(staleIDStr)
+			if err != nil {
+				slog.Warn("Scheduler (Full Sync): Skipping malformed mod id in type index.", "type", modType, "mod_id", staleIDStr)
+				continue
 			}
+			changes.Delete(staleID, itemTypeTag)
 		}
 
 		for i := range modsFromAPI {
-			mod := &modsFromAPI[i] // Iterate by index to get addressable mod for pipeline
+			mod := &modsFromAPI[i] // Iterate by index to get addressable mod for the change set
 			if mod.DateUpdated > maxModUpdateTimestampForThisType {
 				maxModUpdateTimestampForThisType = mod.DateUpdated
 			}
-			
-			oldModData, _ := s.modRepo.GetModByID(ctx, mod.ID)
-			if oldModData != nil {
-				s.modRepo.RemoveOrphanedTagIndexEntries(ctx, pipe, oldModData, mod, itemTypeTag)
-			}
-			
-			err := s.modRepo.AddModCommandsToPipeline(ctx, pipe, mod, itemTypeTag)
-			if err != nil {
-				slog.Error("Scheduler (Full Sync): Failed to add save commands for mod to pipeline.", "mod_id", mod.ID, "error", err)
-			}
+			changes.Upsert(mod, itemTypeTag)
 		}
-		
-		if pipe.Len() > 0 {
-			slog.Info("Scheduler (Full Sync): Executing Redis pipeline for type.", "type", itemTypeTag, "commands_in_pipe", pipe.Len())
-			if _, err := pipe.Exec(ctx); err != nil {
-				return 0, fmt.Errorf("failed to execute Redis pipeline for %s: %w", itemTypeTag, err)
-			}
+
+		slog.Info("Scheduler (Full Sync): Applying changes for type.", "type", itemTypeTag, "upserts", len(modsFromAPI), "deletes", len(staleIDs))
+		s.reportProgress(ctx, progress, SyncProgress{
+			Stage:              SyncStageIndexing,
+			Type:               itemTypeTag,
+			ModsFetched:        len(modsFromAPI),
+			TotalMods:          len(modsFromAPI),
+			EstimatedRemaining: len(modsFromAPI),
+		})
+		summary, err := s.modRepo.ApplyChanges(ctx, changes)
+		if err != nil {
+			return 0, fmt.Errorf("failed to apply changes for %s: %w", itemTypeTag, err)
+		}
+		syncSummary.Add(summary)
+		s.recordFullSyncTotals(ctx, itemTypeTag, modioTotal)
+		if err := s.modRepo.SetLastWriteTimestampForType(ctx, itemTypeTag, time.Now().UTC()); err != nil {
+			slog.Error("Scheduler (Full Sync): Failed to update last write timestamp of type.", "type", itemTypeTag, "error", err)
 		}
 		slog.Info("Scheduler (Full Sync): Successfully synchronized type.", "type", itemTypeTag)
+		s.reportProgress(ctx, progress, SyncProgress{Stage: SyncStageTypeCompleted, Type: itemTypeTag, ModsFetched: len(modsFromAPI), ModsProcessed: len(modsFromAPI), TotalMods: len(modsFromAPI)})
 		return maxModUpdateTimestampForThisType, nil
 	}
 
 	var overallMaxModUpdateTimestamp int64 = 0
-	
+
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, 10*time.Minute) // Increased timeout for full sync
 	defer cancel()
 
-	maxTsMaps, errMaps := processType(modio.MapTag, mapPageCountSafeguard)
-	if errMaps != nil {
-		slog.Error("Scheduler (Full Sync): Error processing maps.", "error", errMaps)
-	} else {
-		if maxTsMaps > overallMaxModUpdateTimestamp {
-			overallMaxModUpdateTimestamp = maxTsMaps
+	var typeErrs []error
+	for _, t := range s.syncTypes {
+		if opts.typeTag != "" && t.tag != opts.typeTag {
+			continue
 		}
-	}
-
-	maxTsScripts, errScripts := processType(modio.ScriptModTag, scriptPageCountSafeguard)
-	if errScripts != nil {
-		slog.Error("Scheduler (Full Sync): Error processing scripts.", "error", errScripts)
-	} else {
-		if maxTsScripts > overallMaxModUpdateTimestamp {
-			overallMaxModUpdateTimestamp = maxTsScripts
+		maxTs, err := processType(t.tag, t.pageSafeguard)
+		if err != nil {
+			if s.noteAuthFailure(err) || s.noteRateLimited(err) {
+				typeErrs = append(typeErrs, err)
+				break // Every other type would be rejected the same way
+			}
+			logSyncError(fullSyncLogPrefix, "Error processing type.", err, "type", t.tag)
+			typeErrs = append(typeErrs, err)
+			continue
+		}
+		s.ClearAuthFailure()
+		if maxTs > overallMaxModUpdateTimestamp {
+			overallMaxModUpdateTimestamp = maxTs
 		}
 	}
-
-	if errMaps == nil && errScripts == nil {
-		slog.Info("Scheduler (Full Sync): Both maps and scripts processed. Updating timestamps.")
-		if overallMaxModUpdateTimestamp > 0 {
+	syncErr := errors.Join(typeErrs...)
+
+	if syncErr == nil {
+		slog.Info("Scheduler (Full Sync): All requested types processed. Updating timestamps.", "type", opts.typeTag)
+		if opts.typeTag != "" {
+			slog.Info("Scheduler (Full Sync): Single-type sync, leaving the event cursor unchanged.", "type", opts.typeTag)
+		} else if opts.preserveEventCursor {
+			s.seedEventCursorIfMissing(ctxWithTimeout, overallMaxModUpdateTimestamp)
+		} else if overallMaxModUpdateTimestamp > 0 {
 			if err := s.modRepo.SetSchedulerLastSyncEventTimestamp(ctxWithTimeout, overallMaxModUpdateTimestamp); err != nil {
 				slog.Error("Scheduler (Full Sync): Failed to update last sync event timestamp after full sync.", "error", err)
 			} else {
 				slog.Info("Scheduler (Full Sync): Updated last sync event timestamp after full sync.", "timestamp", overallMaxModUpdateTimestamp)
 			}
 		}
+		s.bumpSyncGeneration(ctxWithTimeout, "full_sync", syncSummary)
+		if opts.typeTag == "" {
+			s.fullSyncCompleted.Store(true)
+		}
 	} else {
 		slog.Warn("Scheduler (Full Sync): One or more types failed to process during full sync. Timestamps might not be fully updated.")
+		if len(syncSummary.UpsertedIDs) > 0 || len(syncSummary.DeletedIDs) > 0 {
+			// Types that did sync still wrote; without a generation their changes would never reach the feed.
+			s.bumpSyncGeneration(ctxWithTimeout, "full_sync", syncSummary)
+		}
 	}
-	
-	if err := s.modRepo.SetLastOverallWriteTimestamp(ctxWithTimeout, time.Now().UTC()); err != nil {
+
+	if isContextError(syncErr) {
+		// A timed-out or cancelled sync must not look like a fresh write to staleness checks.
+		slog.Warn("Scheduler (Full Sync): Sync stopped by its context, leaving the last overall write timestamp unchanged.")
+	} else if err := s.modRepo.SetLastOverallWriteTimestamp(ctxWithTimeout, time.Now().UTC()); err != nil {
 		slog.Error("Scheduler (Full Sync): Failed to update last overall write timestamp.", "error", err)
 	}
 
 	slog.Info("Scheduler (Full Sync): Full data synchronization cycle finished.")
+	if syncErr != nil {
+		s.reportProgress(ctx, progress, SyncProgress{Stage: SyncStageFailed, Error: syncErr.Error()})
+	} else {
+		s.reportProgress(ctx, progress, SyncProgress{Stage: SyncStageCompleted})
+	}
+	return syncErr
+}
+
+// isContextError reports whether err comes from the sync's own context (deadline or cancellation)
+// rather than from mod.io or Redis.
+func isContextError(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// logSyncError logs a sync failure. Our own timeouts are logged as a warning saying the sync timed out
+// and cancellations (shutdown, a departed admin client) at info, so neither reads like a mod.io outage.
+func logSyncError(logPrefix, msg string, err error, args ...any) {
+	args = append(args, "error", err)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		slog.Warn(logPrefix+" Sync timed out: "+msg, args...)
+	case errors.Is(err, context.Canceled):
+		slog.Info(logPrefix+" Sync cancelled: "+msg, args...)
+	default:
+		slog.Error(logPrefix+" "+msg, args...)
+	}
+}
+
+// seedEventCursorIfMissing leaves an existing event cursor untouched so event polling replays everything
+// after it; only an empty cursor is seeded.
+func (s *Scheduler) seedEventCursorIfMissing(ctx context.Context, fallbackTs int64) {
+	currentTs, err := s.modRepo.GetSchedulerLastSyncEventTimestamp(ctx)
+	if err != nil {
+		slog.Error("Scheduler (Full Sync): Failed to read event cursor while preserving it.", "error", err)
+		return
+	}
+	if currentTs > 0 {
+		slog.Info("Scheduler (Full Sync): Preserved event cursor so events from the downtime gap are replayed.", "timestamp", currentTs)
+		return
+	}
+	if fallbackTs > 0 {
+		if err := s.modRepo.SetSchedulerLastSyncEventTimestamp(ctx, fallbackTs); err != nil {
+			slog.Error("Scheduler (Full Sync): Failed to seed event cursor.", "error", err)
+		}
+	}
+}
+
+// reportProgress delivers a progress update without ever blocking the sync on a slow or departed reader.
+func (s *Scheduler) reportProgress(ctx context.Context, progress chan<- SyncProgress, p SyncProgress) {
+	if progress == nil {
+		return
+	}
+	select {
+	case progress <- p:
+	case <-ctx.Done():
+	}
+}
+
+// bumpSyncGeneration advances the generation counter after a sync that wrote data, so clients and
+// CDNs keying on it see a new value even when two syncs land within the same second. The new
+// generation is also published for sync-completion SSE subscribers, and the sync's changes are recorded
+// under it for the changes feed, and configured caches are warmed.
+func (s *Scheduler) bumpSyncGeneration(ctx context.Context, syncKind string, summary repository.ChangeSummary) {
+	generation, err := s.modRepo.IncrementSyncGeneration(ctx)
+	if err != nil {
+		slog.Error("Scheduler: Failed to increment sync generation", "sync", syncKind, "error", err)
+		return
+	}
+	slog.Info("Scheduler: Sync generation advanced", "sync", syncKind, "generation", generation)
+	if err := s.modRepo.RecordGenerationChanges(ctx, generation, summary); err != nil {
+		slog.Warn("Scheduler: Failed to record generation changes", "sync", syncKind, "generation", generation, "error", err)
+	}
+	event := repository.SyncCompletedEvent{Sync: syncKind, Generation: generation, CompletedAt: time.Now().UTC()}
+	if err := s.modRepo.PublishSyncCompleted(ctx, event); err != nil {
+		slog.Warn("Scheduler: Failed to publish sync completed event", "sync", syncKind, "generation", generation, "error", err)
+	}
+	s.warmCaches(generation)
+}
+
+// baseContext returns the context background work started outside the tickers should run under.
+func (s *Scheduler) baseContext() context.Context {
+	if s.baseCtx == nil {
+		return context.Background()
+	}
+	return s.baseCtx
+}
+
+// HasCompletedFullSync reports whether a full sync of every type has succeeded since the process started.
+func (s *Scheduler) HasCompletedFullSync() bool {
+	return s.fullSyncCompleted.Load()
+}
+
+// SkippedNoopUpdates returns how many edit events were skipped because the mod was unchanged.
+func (s *Scheduler) SkippedNoopUpdates() int64 {
+	return s.skippedNoopUpdates.Load()
 }
 
 func (s *Scheduler) Start() {
@@ -351,41 +688,99 @@ func (s *Scheduler) Start() {
 		"event_processing_interval", s.cfg.LightweightCheckInterval.String(),
 		"full_sync_interval", s.cfg.CacheRefreshInterval.String(),
 	)
-	
-	baseCtx, cancelAll := context.WithCancel(context.Background()) 
+
+	baseCtx, cancelAll := context.WithCancel(context.Background())
+	s.baseCtx = baseCtx
 	// Store cancelAll if you want to trigger a shutdown of these goroutines from Stop more directly
 	// For now, stopChan handles ticker goroutine, and updateMu prevents new long tasks.
 
+	s.setInitialSyncStatus(func(status *InitialSyncStatus) {
+		status.State, status.StartedAt = InitialSyncRunning, time.Now().UTC()
+	})
 	go func() {
 		slog.Info("Scheduler: Performing initial full data synchronization.")
-		// Use a specific context for this initial task that can be shorter if needed
-		initialSyncCtx, initialSyncCancel := context.WithTimeout(baseCtx, 15*time.Minute) // Timeout for initial sync
-		defer initialSyncCancel()
-		s.runFullSynchronization(initialSyncCtx, "initial_startup")
+		s.runInitialSync(baseCtx)
 	}()
 
+	go s.runWebhookWorker(baseCtx)
+
 	eventProcessingTicker := time.NewTicker(s.cfg.LightweightCheckInterval)
 	fullSyncTicker := time.NewTicker(s.cfg.CacheRefreshInterval)
+	var auditTicker *time.Ticker
+	var auditTick <-chan time.Time // Nil, never firing, while audits are disabled
+	if s.cfg.IntegrityAuditInterval > 0 {
+		auditTicker = time.NewTicker(s.cfg.IntegrityAuditInterval)
+		auditTick = auditTicker.C
+	}
+	var downloadTicker *time.Ticker
+	var downloadTick <-chan time.Time // Nil while the download link refresher is disabled
+	if s.cfg.DownloadRefreshInterval > 0 {
+		downloadTicker = time.NewTicker(s.cfg.DownloadRefreshInterval)
+		downloadTick = downloadTicker.C
+	}
 
 	go func() {
 		defer slog.Info("Scheduler: Ticker goroutine stopped.")
 		defer eventProcessingTicker.Stop()
 		defer fullSyncTicker.Stop()
+		if auditTicker != nil {
+			defer auditTicker.Stop()
+		}
+		if downloadTicker != nil {
+			defer downloadTicker.Stop()
+		}
+		var debounceTimer *time.Timer
+		var debounceTick <-chan time.Time // Nil while no on-demand event sync is pending
+		defer func() {
+			if debounceTimer != nil {
+				debounceTimer.Stop()
+			}
+		}()
 
 		for {
 			select {
 			case <-eventProcessingTicker.C:
 				slog.Info("Scheduler: Event processing tick received.")
+				if debounceTimer != nil {
+					// This cycle covers the pending on-demand one.
+					debounceTimer.Stop()
+					debounceTimer, debounceTick = nil, nil
+				}
 				// Use a specific context for each event processing cycle
 				eventCtx, eventCancel := context.WithTimeout(baseCtx, 5*time.Minute) // Timeout for one event cycle
 				s.processRecentChangesViaEvents(eventCtx, "scheduled_event_processing")
 				eventCancel()
+			case <-s.eventSyncReqs:
+				if debounceTimer == nil {
+					debounceTimer = time.NewTimer(s.cfg.EventSyncDebounce)
+					debounceTick = debounceTimer.C
+				}
+			case <-debounceTick:
+				debounceTimer, debounceTick = nil, nil
+				eventCtx, eventCancel := context.WithTimeout(baseCtx, 5*time.Minute)
+				s.processRecentChangesViaEvents(eventCtx, "debounced_event_sync")
+				eventCancel()
+				// Restart the schedule so the next tick doesn't repeat the cycle that just ran.
+				eventProcessingTicker.Reset(s.cfg.LightweightCheckInterval)
 			case <-fullSyncTicker.C:
 				slog.Info("Scheduler: Full synchronization tick received.")
 				// Use a specific context for each full sync cycle
 				fullSyncCtx, fullSyncCancel := context.WithTimeout(baseCtx, 30*time.Minute) // Timeout for one full sync cycle
-				s.runFullSynchronization(fullSyncCtx, "scheduled_full_sync")
+				if opts, run := s.planScheduledFullSync(fullSyncCtx); run {
+					s.runFullSynchronization(fullSyncCtx, opts)
+				}
 				fullSyncCancel()
+			case <-downloadTick:
+				if s.pausedForModio("download_refresh") {
+					continue
+				}
+				downloadCtx, downloadCancel := context.WithTimeout(baseCtx, downloadRefreshTimeout)
+				s.refreshExpiringDownloads(downloadCtx)
+				downloadCancel()
+			case <-auditTick:
+				auditCtx, auditCancel := context.WithTimeout(baseCtx, integrityAuditTimeout)
+				s.runIntegrityAudit(auditCtx)
+				auditCancel()
 			case <-s.stopChan:
 				slog.Info("Scheduler: Stop signal received, cancelling base context and exiting ticker goroutine.")
 				cancelAll() // Cancel baseCtx to signal running tasks
@@ -411,3 +806,12 @@ func (s *Scheduler) Stop() {
 	// Note: updateMu will prevent new long operations from starting.
 	// Ongoing operations (event processing or full sync) will complete or timeout based on their own contexts.
 }
+
+// RequestEventSync asks for an event processing cycle within EVENT_SYNC_DEBOUNCE_SECONDS. Requests made
+// while one is pending share it, as does a scheduled cycle that comes first. It never blocks.
+func (s *Scheduler) RequestEventSync() {
+	select {
+	case s.eventSyncReqs <- struct{}{}:
+	default: // A request is already waiting to be picked up
+	}
+}
@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+)
+
+const queuedSyncTimeout = 30 * time.Minute
+
+// ErrSyncQueueFull is returned when an admin full sync is already running and another is queued.
+var ErrSyncQueueFull = errors.New("a full sync is running and another is already queued")
+
+// QueuedSync is an admin-requested full sync, either running or waiting its turn.
+type QueuedSync struct {
+	Type        string     `json:"type,omitempty"` // Type name, e.g. "map"; empty for all types
+	RequestedAt time.Time  `json:"requestedAt"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"` // Nil while waiting for a scheduled sync or event cycle to finish
+
+	typeTag string
+}
+
+// SyncQueueState is a snapshot of the admin full sync queue.
+type SyncQueueState struct {
+	Running *QueuedSync `json:"running"`
+	Queued  *QueuedSync `json:"queued"`
+}
+
+// QueueFullSync queues a full sync, of one type when modType is set. At most one queued sync runs at a
+// time and at most one waits behind it; beyond that ErrSyncQueueFull is returned with the current state.
+func (s *Scheduler) QueueFullSync(modType string) (SyncQueueState, error) {
+	var typeTag string
+	if modType != "" {
+		parsed, err := s.ParseSyncType(modType)
+		if err != nil {
+			return SyncQueueState{}, err
+		}
+		typeTag = parsed
+	}
+	request := &QueuedSync{RequestedAt: time.Now().UTC(), typeTag: typeTag}
+	if typeTag != "" {
+		request.Type = repository.GetModTypeFromTag(typeTag)
+	}
+
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	switch {
+	case s.runningSync == nil:
+		s.runningSync = request
+		go s.runQueuedSyncs()
+	case s.queuedSync == nil:
+		s.queuedSync = request
+	default:
+		return s.syncQueueStateLocked(), ErrSyncQueueFull
+	}
+	return s.syncQueueStateLocked(), nil
+}
+
+// SyncQueueState returns the current admin full sync queue.
+func (s *Scheduler) SyncQueueState() SyncQueueState {
+	s.queueMu.Lock()
+	defer s.queueMu.Unlock()
+	return s.syncQueueStateLocked()
+}
+
+func (s *Scheduler) syncQueueStateLocked() SyncQueueState {
+	var state SyncQueueState
+	if s.runningSync != nil {
+		running := *s.runningSync
+		state.Running = &running
+	}
+	if s.queuedSync != nil {
+		queued := *s.queuedSync
+		state.Queued = &queued
+	}
+	return state
+}
+
+// runQueuedSyncs runs the running sync, then promotes and runs the queued one until the queue is empty.
+// Unlike scheduled syncs it waits for updateMu rather than skipping.
+func (s *Scheduler) runQueuedSyncs() {
+	for {
+		s.queueMu.Lock()
+		current := s.runningSync
+		s.queueMu.Unlock()
+
+		s.updateMu.Lock()
+		startedAt := time.Now().UTC()
+		s.queueMu.Lock()
+		current.StartedAt = &startedAt
+		s.queueMu.Unlock()
+
+		ctx, cancel := context.WithTimeout(s.baseContext(), queuedSyncTimeout)
+		if err := s.fullSyncLocked(ctx, fullSyncOptions{triggeredBy: "admin_queue", typeTag: current.typeTag}); err != nil {
+			slog.Warn("Scheduler (Full Sync): Queued admin sync did not complete", "type", current.Type, "error", err)
+		}
+		cancel()
+		s.updateMu.Unlock()
+
+		s.queueMu.Lock()
+		s.runningSync, s.queuedSync = s.queuedSync, nil
+		next := s.runningSync
+		s.queueMu.Unlock()
+		if next == nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+)
+
+// recordFullSyncTotals stores mod.io's result total and the cached count for a type just synchronized,
+// for planScheduledFullSync to compare against. modioTotal is negative if no page reported a total.
+func (s *Scheduler) recordFullSyncTotals(ctx context.Context, typeTag string, modioTotal int) {
+	if modioTotal < 0 {
+		return
+	}
+	cached, err := s.modRepo.CountModsByFilter(ctx, typeTag, nil)
+	if err == nil {
+		err = s.modRepo.SetFullSyncTotals(ctx, typeTag, int64(modioTotal), cached)
+	}
+	if err != nil {
+		slog.Warn("Scheduler (Full Sync): Could not record sync totals; the next scheduled sync runs in full", "type", typeTag, "error", err)
+	}
+}
+
+// planScheduledFullSync decides what a scheduled full sync covers. With FULL_SYNC_SKIP_UNCHANGED set, types
+// whose mod.io total and cached count both still match the last full sync are left out: a stable total
+// with no local deletions means no mod was added or removed, and edits in place are event polling's job.
+// It returns false when every type is unchanged.
+func (s *Scheduler) planScheduledFullSync(ctx context.Context) (fullSyncOptions, bool) {
+	opts := fullSyncOptions{triggeredBy: "scheduled_full_sync"}
+	if !s.cfg.SkipUnchangedFullSync || s.AuthFailure() != nil {
+		return opts, true // runFullSynchronization applies the cooldown
+	}
+	var changed []string
+	for _, t := range s.syncTypes {
+		if !s.typeLooksUnchanged(ctx, t.tag) {
+			changed = append(changed, t.tag)
+		}
+	}
+	switch len(changed) {
+	case 0:
+		slog.Info("Scheduler (Full Sync): Mod.io totals unchanged since the last full sync, skipping scheduled sync.")
+		return opts, false
+	case 1:
+		slog.Info("Scheduler (Full Sync): Only one type changed since the last full sync, syncing it alone.", "type", changed[0])
+		opts.typeTag = changed[0]
+	}
+	return opts, true
+}
+
+// typeLooksUnchanged reports whether a type's mod.io total and cached count match the recorded ones. Any
+// error or missing record counts as changed.
+func (s *Scheduler) typeLooksUnchanged(ctx context.Context, typeTag string) bool {
+	recordedTotal, recordedCached, ok, err := s.modRepo.GetFullSyncTotals(ctx, typeTag)
+	if err != nil {
+		slog.Warn("Scheduler (Full Sync): Could not read recorded sync totals", "type", typeTag, "error", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	page, err := s.modioClient.FetchModsPage(ctx, s.modioClient.TypeFilters(typeTag), 0, 1)
+	if err != nil {
+		s.noteAuthFailure(err)
+		slog.Warn("Scheduler (Full Sync): Could not read current Mod.io total", "type", typeTag, "error", err)
+		return false
+	}
+	cached, err := s.modRepo.CountModsByFilter(ctx, typeTag, nil)
+	if err != nil {
+		slog.Warn("Scheduler (Full Sync): Could not count cached mods", "type", typeTag, "error", err)
+		return false
+	}
+	unchanged := int64(page.ResultTotal) == recordedTotal && cached == recordedCached
+	slog.Debug("Scheduler (Full Sync): Compared sync totals.", "type", typeTag, "modio_total", page.ResultTotal, "recorded_total", recordedTotal, "cached", cached, "recorded_cached", recordedCached, "unchanged", unchanged)
+	return unchanged
+}
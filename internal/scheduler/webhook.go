@@ -0,0 +1,96 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+)
+
+const webhookQueueSize = 256
+
+// ErrWebhookQueueFull is returned when webhook events arrive faster than they can be applied.
+var ErrWebhookQueueFull = errors.New("webhook event queue is full")
+
+// EnqueueWebhookEvents queues pushed mod.io events for the webhook worker. Nothing is queued if they
+// don't all fit; event polling remains the fallback for anything a full queue turns away.
+func (s *Scheduler) EnqueueWebhookEvents(events []modio.ModioEvent) error {
+	if len(events) > webhookQueueSize-len(s.webhookEvents) {
+		return ErrWebhookQueueFull
+	}
+	for _, event := range events {
+		select {
+		case s.webhookEvents <- event:
+		default:
+			return ErrWebhookQueueFull
+		}
+	}
+	return nil
+}
+
+// runWebhookWorker applies queued webhook events until ctx is cancelled. It waits for the update lock
+// rather than skipping, so a running sync only delays pushed events.
+func (s *Scheduler) runWebhookWorker(ctx context.Context) {
+	defer slog.Info("Scheduler (Webhook): Worker stopped.")
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-s.webhookEvents:
+			batch := []modio.ModioEvent{event}
+			for drained := false; !drained && len(batch) < modEventsPageLimit; {
+				select {
+				case next := <-s.webhookEvents:
+					batch = append(batch, next)
+				default:
+					drained = true
+				}
+			}
+			s.applyWebhookEvents(ctx, batch)
+		}
+	}
+}
+
+// applyWebhookEvents processes a batch like event polling does, but leaves the event cursor alone:
+// pushed events can arrive ahead of older ones polling has not seen yet, and replaying them is a no-op.
+func (s *Scheduler) applyWebhookEvents(ctx context.Context, events []modio.ModioEvent) {
+	s.updateMu.Lock()
+	defer s.updateMu.Unlock()
+
+	eventCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	seenEventIDs := s.findProcessedEventIDs(eventCtx, events)
+	details := s.prefetchModDetails(eventCtx, events, seenEventIDs)
+	changes := &repository.ChangeSet{}
+	for _, event := range events {
+		if seenEventIDs[event.ID] {
+			continue
+		}
+		outcome, err := s.processEvent(eventCtx, changes, details, event)
+		if err != nil {
+			logSyncError("Scheduler (Webhook):", "Stopped while processing webhook event.", err, "mod_id", event.ModID, "event_type", event.EventType)
+			return
+		}
+		if outcome != eventNotApplied && event.ID != 0 {
+			changes.MarkEventsProcessed(s.cfg.EventDedupWindow, event.ID)
+			seenEventIDs[event.ID] = true // mod.io may resend an event within one payload
+		}
+	}
+	summary, err := s.modRepo.ApplyChanges(eventCtx, changes)
+	if err != nil {
+		logSyncError("Scheduler (Webhook):", "Failed to apply changes for webhook events", err)
+		return
+	}
+	if changes.Len() == 0 {
+		return
+	}
+	if err := s.modRepo.SetLastOverallWriteTimestamp(eventCtx, time.Now().UTC()); err != nil {
+		slog.Error("Scheduler (Webhook): Failed to update last overall write timestamp", "error", err)
+	}
+	s.bumpSyncGeneration(eventCtx, "webhook", summary)
+	slog.Info("Scheduler (Webhook): Applied webhook events.", "count", len(events))
+}
@@ -0,0 +1,518 @@
+package server
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/config"
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+	"github.com/ShawnEdgell/modio-api-go/internal/scheduler"
+	"github.com/go-chi/chi/v5"
+)
+
+type ConfigResponse struct {
+	Values          []config.ConfigValue `json:"values"`
+	UnrecognizedEnv []string             `json:"unrecognizedEnv"`
+}
+
+// ConfigHandler reports each setting's effective value and whether it came from the environment or a
+// default, so a typo'd variable name shows up as a default. Secrets are redacted.
+func ConfigHandler(cfg *config.AppConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		unrecognized := cfg.UnrecognizedEnv
+		if unrecognized == nil {
+			unrecognized = []string{}
+		}
+		writeJSONResponse(w, http.StatusOK, ConfigResponse{Values: cfg.Values, UnrecognizedEnv: unrecognized})
+	}
+}
+
+// exportCSVColumns is the fixed column set of /admin/export.csv.
+var exportCSVColumns = []string{"id", "name", "downloads", "subscribers", "date_updated", "tags"}
+
+// ExportCSVHandler streams a type's cached mods (?type=map, or another configured type) as CSV, writing
+// rows as the cache is scanned. date_updated is RFC 3339 UTC and tags are joined with "; ".
+func ExportCSVHandler(modRepo *repository.ModRepository, dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		typeTag, err := dataScheduler.ParseSyncType(r.URL.Query().Get("type"))
+		if err != nil {
+			http.Error(w, "Invalid 'type' query parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Exports of the whole catalog can outlast the server's WriteTimeout on slow links.
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(60 * time.Second)); err != nil {
+			slog.Warn("Could not extend write deadline for CSV export", "error", err)
+		}
+
+		modType := repository.GetModTypeFromTag(typeTag)
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", modType+"s.csv"))
+
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportCSVColumns); err != nil {
+			slog.Info("CSV export client went away", "type", modType, "error", err)
+			return
+		}
+		rows := 0
+		err = modRepo.ScanModsByType(r.Context(), typeTag, func(mod *modio.Mod) error {
+			tags := make([]string, len(mod.Tags))
+			for i, tag := range mod.Tags {
+				tags[i] = tag.Name
+			}
+			rows++
+			return cw.Write([]string{
+				strconv.Itoa(mod.ID),
+				mod.Name,
+				strconv.Itoa(mod.Stats.DownloadsTotal),
+				strconv.Itoa(mod.Stats.SubscribersTotal),
+				time.Unix(mod.DateUpdated, 0).UTC().Format(time.RFC3339),
+				strings.Join(tags, "; "),
+			})
+		})
+		cw.Flush()
+		if err == nil {
+			err = cw.Error()
+		}
+		if err != nil {
+			// The status and some rows are already sent, so the truncated file is all the client gets.
+			slog.Error("CSV export failed mid-stream", "type", modType, "rows_written", rows, "error", err)
+		}
+	}
+}
+
+type FieldDiff struct {
+	Field  string      `json:"field"`
+	Cached interface{} `json:"cached"`
+	Live   interface{} `json:"live"`
+}
+
+type ModDiffResponse struct {
+	ModID         int         `json:"modId"`
+	InCache       bool        `json:"inCache"`
+	OnModio       bool        `json:"onModio"`
+	ChangedFields []FieldDiff `json:"changedFields"`
+}
+
+// ModDiffHandler compares a mod's cached copy against the live mod.io object field by field,
+// which helps verify the scheduler is keeping data fresh.
+func ModDiffHandler(modRepo *repository.ModRepository, modioClient *modio.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		modID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || modID <= 0 {
+			http.Error(w, "Invalid mod id", http.StatusBadRequest)
+			return
+		}
+
+		cachedMod, err := modRepo.GetModByID(r.Context(), modID)
+		if err != nil {
+			slog.Error("Failed to get cached mod for diff", "mod_id", modID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		liveMod, err := modioClient.GetModDetails(r.Context(), modID)
+		if errors.Is(err, modio.ErrNotFound) {
+			liveMod, err = nil, nil
+		}
+		if err != nil {
+			slog.Error("Failed to fetch live mod for diff", "mod_id", modID, "error", err)
+			http.Error(w, "Bad Gateway", http.StatusBadGateway)
+			return
+		}
+
+		if cachedMod != nil {
+			if err := modRepo.RestoreFullDescription(r.Context(), cachedMod); err != nil {
+				slog.Warn("Diffing against the truncated description", "mod_id", modID, "error", err)
+			}
+		}
+
+		if cachedMod == nil && liveMod == nil {
+			http.Error(w, "Mod not found in cache or on mod.io", http.StatusNotFound)
+			return
+		}
+
+		changes, err := diffMods(cachedMod, liveMod)
+		if err != nil {
+			slog.Error("Failed to diff mods", "mod_id", modID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSONResponse(w, http.StatusOK, ModDiffResponse{
+			ModID:         modID,
+			InCache:       cachedMod != nil,
+			OnModio:       liveMod != nil,
+			ChangedFields: changes,
+		})
+	}
+}
+
+// diffMods flattens both mods into dotted JSON paths (e.g. "stats.downloads_total") and reports every
+// path whose value differs. Arrays are compared as a whole. A nil mod contributes no fields.
+func diffMods(cached, live *modio.Mod) ([]FieldDiff, error) {
+	cachedFields, err := flattenMod(cached)
+	if err != nil {
+		return nil, err
+	}
+	liveFields, err := flattenMod(live)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldNames := make(map[string]struct{}, len(cachedFields))
+	for name := range cachedFields {
+		fieldNames[name] = struct{}{}
+	}
+	for name := range liveFields {
+		fieldNames[name] = struct{}{}
+	}
+
+	changes := make([]FieldDiff, 0)
+	for name := range fieldNames {
+		cachedValue, liveValue := cachedFields[name], liveFields[name]
+		if !reflect.DeepEqual(cachedValue, liveValue) {
+			changes = append(changes, FieldDiff{Field: name, Cached: cachedValue, Live: liveValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+func flattenMod(mod *modio.Mod) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if mod == nil {
+		return fields, nil
+	}
+	modJSON, err := json.Marshal(mod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal mod %d: %w", mod.ID, err)
+	}
+	var generic map[string]interface{}
+	if err := json.Unmarshal(modJSON, &generic); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mod %d: %w", mod.ID, err)
+	}
+	flattenInto(fields, "", generic)
+	return fields, nil
+}
+
+func flattenInto(fields map[string]interface{}, prefix string, value map[string]interface{}) {
+	for key, v := range value {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			flattenInto(fields, path, nested)
+			continue
+		}
+		fields[path] = v
+	}
+}
+
+const maxRefreshBatchSize = 100
+
+type RefreshRequest struct {
+	IDs []int `json:"ids"`
+}
+
+type RefreshResponse struct {
+	Count   int                       `json:"count"`
+	Results []scheduler.RefreshResult `json:"results"`
+}
+
+// RefreshModsHandler force-refreshes the mods listed in a {"ids": [...]} body from mod.io.
+func RefreshModsHandler(dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req RefreshRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+			http.Error(w, "Invalid JSON body: expected {\"ids\": [...]}", http.StatusBadRequest)
+			return
+		}
+
+		seen := make(map[int]bool, len(req.IDs))
+		modIDs := make([]int, 0, len(req.IDs))
+		for _, id := range req.IDs {
+			if id <= 0 {
+				http.Error(w, fmt.Sprintf("Invalid mod id: %d", id), http.StatusBadRequest)
+				return
+			}
+			if !seen[id] {
+				seen[id] = true
+				modIDs = append(modIDs, id)
+			}
+		}
+		if len(modIDs) == 0 {
+			http.Error(w, "No mod ids given", http.StatusBadRequest)
+			return
+		}
+		if len(modIDs) > maxRefreshBatchSize {
+			http.Error(w, fmt.Sprintf("Too many mod ids: at most %d per request", maxRefreshBatchSize), http.StatusBadRequest)
+			return
+		}
+
+		results, err := dataScheduler.RefreshMods(r.Context(), modIDs)
+		if errors.Is(err, scheduler.ErrSyncInProgress) {
+			http.Error(w, "A sync is already in progress", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			slog.Error("Targeted refresh failed", "count", len(modIDs), "error", err)
+			http.Error(w, "Bad Gateway", http.StatusBadGateway)
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, RefreshResponse{Count: len(results), Results: results})
+	}
+}
+
+type CursorResetRequest struct {
+	Timestamp int64 `json:"timestamp"` // Unix seconds; zero or omitted means now minus the repair lookback
+}
+
+// ResetEventCursorHandler manually moves the event cursor, e.g. after it stalled on a bad timestamp.
+// The body is optional.
+func ResetEventCursorHandler(dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req CursorResetRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "Invalid JSON body: expected {\"timestamp\": <unix seconds>}", http.StatusBadRequest)
+			return
+		}
+		if req.Timestamp < 0 {
+			http.Error(w, "Invalid timestamp: must not be negative", http.StatusBadRequest)
+			return
+		}
+
+		reset, err := dataScheduler.ResetEventCursor(r.Context(), req.Timestamp)
+		if errors.Is(err, scheduler.ErrInvalidCursor) {
+			http.Error(w, "Invalid timestamp: must not be in the future", http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, scheduler.ErrSyncInProgress) {
+			http.Error(w, "A sync is already in progress", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to reset event cursor", "timestamp", req.Timestamp, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, reset)
+	}
+}
+
+type IntegrityAuditResponse struct {
+	Enabled   bool                            `json:"enabled"`
+	LastAudit *scheduler.IntegrityAuditResult `json:"lastAudit"` // Nil until the first audit completes
+}
+
+// IntegrityAuditHandler reports the latest background integrity audit.
+func IntegrityAuditHandler(cfg *config.AppConfig, dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, http.StatusOK, IntegrityAuditResponse{
+			Enabled:   cfg.IntegrityAuditInterval > 0,
+			LastAudit: dataScheduler.LastIntegrityAudit(),
+		})
+	}
+}
+
+type ModioKeyRequest struct {
+	APIKey string `json:"apiKey"`
+}
+
+// ModioKeyHandler rotates the mod.io API key at runtime from a {"apiKey": "..."} body. The new key lasts
+// until restart, so update MODIO_API_KEY as well. A recorded auth failure is cleared so scheduled syncs
+// resume with the new key.
+func ModioKeyHandler(modioClient *modio.Client, dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req ModioKeyRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&req); err != nil || strings.TrimSpace(req.APIKey) == "" {
+			http.Error(w, "Invalid JSON body: expected {\"apiKey\": \"...\"}", http.StatusBadRequest)
+			return
+		}
+		if err := modioClient.SetAPIKey(strings.TrimSpace(req.APIKey)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		dataScheduler.ClearAuthFailure()
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Statuses of an admin sync request.
+const (
+	SyncRequested = "requested" // An event cycle will run after the debounce
+	SyncStarted   = "started"
+	SyncQueued    = "queued" // Behind a running full sync
+	SyncSkipped   = "skipped"
+)
+
+type FullSyncQueueResponse struct {
+	Status string `json:"status,omitempty"` // For POST, see the Sync* statuses
+	Error  string `json:"error,omitempty"`
+	scheduler.SyncQueueState
+}
+
+// FullSyncHandler queues a full sync (?type=map|script for one type) and returns 202 with the queue state.
+// With a sync running and another queued it returns 429 and the state instead of queueing more.
+func FullSyncHandler(dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var state scheduler.SyncQueueState
+		var err error
+		if modType := r.URL.Query().Get("type"); modType != "" {
+			state, err = dataScheduler.QueueFullSync(modType)
+		} else {
+			state, err = dataScheduler.TriggerFullSync(r.Context())
+		}
+		if errors.Is(err, scheduler.ErrUnknownType) {
+			http.Error(w, "Invalid 'type' query parameter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, scheduler.ErrSyncQueueFull) {
+			writeJSONResponse(w, http.StatusTooManyRequests, FullSyncQueueResponse{Status: SyncSkipped, Error: err.Error(), SyncQueueState: state})
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to queue full sync", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		status := SyncStarted
+		if state.Queued != nil { // Ours; a request finding one queued already gets ErrSyncQueueFull
+			status = SyncQueued
+		}
+		slog.Info("Admin full sync requested", "status", status, "type", r.URL.Query().Get("type"))
+		writeJSONResponse(w, http.StatusAccepted, FullSyncQueueResponse{Status: status, SyncQueueState: state})
+	}
+}
+
+// InitialSyncHandler reports the startup full sync: state, attempts, last attempt's duration and error,
+// and the next retry.
+func InitialSyncHandler(dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, http.StatusOK, dataScheduler.InitialSyncStatus())
+	}
+}
+
+type EventSyncResponse struct {
+	Status string `json:"status"` // See the Sync* statuses
+	Error  string `json:"error,omitempty"`
+}
+
+// EventSyncHandler requests an event processing cycle. Bursts of requests within the debounce window
+// share one cycle, so it answers 202 without waiting for it. ?immediate=true starts the cycle at once
+// instead, or answers 409 while another sync holds the update lock.
+func EventSyncHandler(dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		immediate := false
+		if immediateStr := r.URL.Query().Get("immediate"); immediateStr != "" {
+			parsed, err := strconv.ParseBool(immediateStr)
+			if err != nil {
+				http.Error(w, "Invalid 'immediate' query parameter: expected true or false", http.StatusBadRequest)
+				return
+			}
+			immediate = parsed
+		}
+		if !immediate {
+			dataScheduler.RequestEventSync()
+			writeJSONResponse(w, http.StatusAccepted, EventSyncResponse{Status: SyncRequested})
+			return
+		}
+		if err := dataScheduler.TriggerEventSync(r.Context()); err != nil {
+			if !errors.Is(err, scheduler.ErrSyncInProgress) {
+				slog.Error("Failed to start admin event sync", "error", err)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			writeJSONResponse(w, http.StatusConflict, EventSyncResponse{Status: SyncSkipped, Error: err.Error()})
+			return
+		}
+		slog.Info("Admin event sync started")
+		writeJSONResponse(w, http.StatusAccepted, EventSyncResponse{Status: SyncStarted})
+	}
+}
+
+// FullSyncQueueHandler reports the admin full sync queue.
+func FullSyncQueueHandler(dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, http.StatusOK, FullSyncQueueResponse{SyncQueueState: dataScheduler.SyncQueueState()})
+	}
+}
+
+// FullSyncStreamHandler triggers a full sync and streams its progress as server-sent events. ?type=map (or
+// another configured type) syncs only that type. Disconnecting the client cancels the sync.
+func FullSyncStreamHandler(dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		var typeTag string
+		if typeParam := r.URL.Query().Get("type"); typeParam != "" {
+			parsed, err := dataScheduler.ParseSyncType(typeParam)
+			if err != nil {
+				http.Error(w, "Invalid 'type' query parameter: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			typeTag = parsed
+		}
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+
+		progress, err := dataScheduler.StreamFullSync(ctx, "admin_stream", typeTag)
+		if errors.Is(err, scheduler.ErrSyncInProgress) {
+			http.Error(w, "A sync is already in progress", http.StatusConflict)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to start streamed full sync", "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		// A full sync outlives the server's WriteTimeout, so lift the deadline for this response only.
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+			slog.Warn("Could not clear write deadline for SSE stream", "error", err)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		for p := range progress {
+			if err := writeSSEEvent(w, "progress", p); err != nil {
+				slog.Info("Full sync stream client went away, cancelling sync", "error", err)
+				cancel()
+				continue // Drain until the sync observes the cancellation and closes the channel
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal SSE payload: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+)
+
+// modETag identifies a mod's detail response by its update time and current modfile. Stats changes
+// don't alter it, so it is weak: a 304 may leave a client with slightly old counters.
+func modETag(mod *modio.Mod) string {
+	return fmt.Sprintf(`W/"%d-%d-%d"`, mod.ID, mod.DateUpdated, mod.Modfile.ID)
+}
+
+// listETag identifies a cached list response by the sync generation, the last write to the cache and the
+// number of mods the list matched; the generation tells apart syncs that land within the same millisecond.
+// It is weak because it doesn't cover ?sort=hot rankings drifting as time passes; the query string isn't
+// part of it either, since caches already key validators by URL.
+func listETag(generation int64, lastUpdated time.Time, totalCount int) string {
+	return fmt.Sprintf(`W/"%d-%d-%d"`, generation, lastUpdated.UnixMilli(), totalCount)
+}
+
+// checkNotModified sets the ETag and Last-Modified validators and, when the request's If-None-Match (or,
+// without one, If-Modified-Since) matches them, answers 304 and reports true. A zero lastModified sends
+// no Last-Modified.
+func checkNotModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	notModified := false
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		notModified = etagListMatches(inm, etag)
+	} else if ims := r.Header.Get("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		if since, err := http.ParseTime(ims); err == nil {
+			notModified = !lastModified.Truncate(time.Second).After(since)
+		}
+	}
+	if notModified {
+		w.WriteHeader(http.StatusNotModified)
+	}
+	return notModified
+}
+
+// etagListMatches compares an If-None-Match list against etag with the weak comparison RFC 9110 requires.
+func etagListMatches(list, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package server
+
+import "net/http"
+
+// X-Data-Source values, naming the layer a response's data was read from. Handlers set the header only
+// on successful data responses.
+const (
+	DataSourceRedis = "redis"
+	DataSourceLive  = "live" // Read from mod.io during the request
+)
+
+func setDataSource(w http.ResponseWriter, source string) {
+	w.Header().Set("X-Data-Source", source)
+}
@@ -1,91 +1,410 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
+	"github.com/ShawnEdgell/modio-api-go/internal/config"
 	"github.com/ShawnEdgell/modio-api-go/internal/modio"
 	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+	"github.com/ShawnEdgell/modio-api-go/internal/scheduler"
+	"github.com/go-chi/chi/v5"
 	// For health check ping
 )
 
+const (
+	SyncStatusPending = "pending" // No sync has completed yet, an empty list may just mean a cold start
+	SyncStatusReady   = "ready"
+)
+
 type APIResponse struct {
 	ItemType    string      `json:"itemType"`
-	LastUpdated time.Time   `json:"lastUpdated"`
+	LastUpdated *time.Time  `json:"lastUpdated,omitempty"` // Omitted when unknown rather than emitting the zero time
+	SyncStatus  string      `json:"syncStatus"`
+	Source      string      `json:"source"` // "cache", or "live" when served from mod.io before the first sync
+	Page        int         `json:"page"`
+	PerPage     int         `json:"perPage"`
+	TotalCount  int         `json:"totalCount"` // Mods across all pages; Count is this page's
 	Count       int         `json:"count"`
+	Dropped     int         `json:"dropped,omitempty"` // Mods that could not be loaded; the list is partial when set
 	Items       []modio.Mod `json:"items"`
 }
 
 type AutocompleteSuggestion struct {
-	ID    int    `json:"id"`
-	Title string `json:"title"`
+	ID    int        `json:"id"`
+	Title string     `json:"title"`           // The original title; the indexed (normalized) one if the mod can't be loaded
+	Match *MatchSpan `json:"match,omitempty"` // Set with ?highlight=true
+}
+
+var jsonEncodeFailures atomic.Int64
+
+// JSONEncodeFailures returns how many responses failed to encode and were answered with a 500 instead.
+func JSONEncodeFailures() int64 {
+	return jsonEncodeFailures.Load()
 }
 
+// writeJSONResponse encodes data before writing anything, so an encoding failure becomes a clean 500
+// rather than a success status followed by a truncated body.
 func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	var body bytes.Buffer
+	if data != nil {
+		if err := json.NewEncoder(&body).Encode(data); err != nil {
+			jsonEncodeFailures.Add(1)
+			slog.Error("Failed to encode JSON response", "status", statusCode, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	if data != nil {
-		if err := json.NewEncoder(w).Encode(data); err != nil {
-			slog.Error("Failed to encode JSON response", "error", err)
-			// Can't write http.Error here as headers/status might be sent
+	if _, err := w.Write(body.Bytes()); err != nil {
+		slog.Debug("Failed to write JSON response", "error", err)
+	}
+}
+
+// ModListHandler serves the list endpoint of one configured category.
+func ModListHandler(modRepo *repository.ModRepository, live *liveFallthrough, present presenter, hot hotRanking, strictParams bool, itemTypeTag string) http.HandlerFunc {
+	return modListHandler(modRepo, live, present, hot, strictParams, itemTypeTag, itemTypeName(itemTypeTag))
+}
+
+// itemTypeName is a category's plural name, used in its routes and as itemType in responses ("Map" is
+// "maps").
+func itemTypeName(itemTypeTag string) string {
+	return repository.GetModTypeFromTag(itemTypeTag) + "s"
+}
+
+// listQueryParams are the query parameters the list endpoints understand.
+var listQueryParams = map[string]bool{"hasMedia": true, "limit": true, "page": true, "sort": true, "strict": true, "tagMatch": true, "tags": true}
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+const maxListTags = 20
+
+// parseListFilter reads ?hasMedia=, ?tags= and ?tagMatch= of a list request, answering 400 when one is invalid.
+func parseListFilter(w http.ResponseWriter, r *http.Request) (repository.ListFilter, bool) {
+	var filter repository.ListFilter
+	query := r.URL.Query()
+	if hasMediaStr := query.Get("hasMedia"); hasMediaStr != "" {
+		parsed, err := strconv.ParseBool(hasMediaStr)
+		if err != nil {
+			http.Error(w, "Invalid 'hasMedia' query parameter: expected true or false", http.StatusBadRequest)
+			return filter, false
 		}
+		filter.HasMedia = &parsed
 	}
+	for _, tag := range strings.Split(query.Get("tags"), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(filter.Tags, tag) {
+			filter.Tags = append(filter.Tags, tag)
+		}
+	}
+	if len(filter.Tags) > maxListTags {
+		http.Error(w, fmt.Sprintf("Invalid 'tags' query parameter: at most %d tags", maxListTags), http.StatusBadRequest)
+		return filter, false
+	}
+	switch query.Get("tagMatch") {
+	case "", "all":
+	case "any":
+		filter.AnyTag = true
+	default:
+		http.Error(w, "Invalid 'tagMatch' query parameter: expected all or any", http.StatusBadRequest)
+		return filter, false
+	}
+	return filter, true
 }
 
-func MapsHandler(modRepo *repository.ModRepository) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-			return
+// parseListPage reads the 1-based ?page= and ?limit= of a list request, answering 400 when either is invalid.
+func parseListPage(w http.ResponseWriter, r *http.Request) (page, limit int, ok bool) {
+	page, limit = 1, defaultListLimit
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		p, err := strconv.Atoi(pageStr)
+		if err != nil || p < 1 {
+			http.Error(w, "Invalid 'page' query parameter: must be a positive integer", http.StatusBadRequest)
+			return 0, 0, false
+		}
+		page = p
+	}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 || l > maxListLimit {
+			http.Error(w, fmt.Sprintf("Invalid 'limit' query parameter: must be an integer between 1 and %d", maxListLimit), http.StatusBadRequest)
+			return 0, 0, false
 		}
+		limit = l
+	}
+	return page, limit, true
+}
 
-		maps, lastUpdated, err := modRepo.GetModsByType(r.Context(), modio.MapTag)
+// pageBounds returns the slice bounds of page within total items; a page past the end is empty.
+func pageBounds(total, page, limit int) (start, end int) {
+	if page-1 >= (total+limit-1)/limit {
+		return total, total
+	}
+	start = (page - 1) * limit
+	return start, min(start+limit, total)
+}
+
+// checkQueryParams answers 400 listing the unrecognized query parameters when strict validation is on,
+// through STRICT_QUERY_PARAMS or ?strict=true, and reports whether the request may proceed. ?strict=false
+// turns validation off for one request.
+func checkQueryParams(w http.ResponseWriter, r *http.Request, strictByDefault bool, known map[string]bool) bool {
+	query := r.URL.Query()
+	strict := strictByDefault
+	if strictStr := query.Get("strict"); strictStr != "" {
+		parsed, err := strconv.ParseBool(strictStr)
 		if err != nil {
-			slog.Error("Failed to get maps from repository", "error", err)
-			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			return
+			http.Error(w, "Invalid 'strict' query parameter: expected true or false", http.StatusBadRequest)
+			return false
 		}
-
-		response := APIResponse{
-			ItemType:    "maps",
-			LastUpdated: lastUpdated,
-			Count:       len(maps),
-			Items:       maps,
+		strict = parsed
+	}
+	if !strict {
+		return true
+	}
+	var unknown []string
+	for param := range query {
+		if !known[param] {
+			unknown = append(unknown, param)
 		}
-		writeJSONResponse(w, http.StatusOK, response)
 	}
+	if len(unknown) == 0 {
+		return true
+	}
+	sort.Strings(unknown)
+	http.Error(w, "Unknown query parameters: "+strings.Join(unknown, ", "), http.StatusBadRequest)
+	return false
 }
 
-func ScriptsHandler(modRepo *repository.ModRepository) http.HandlerFunc {
+// modListHandler serves a type's cached mods. With a non-nil live fallthrough, a type that has never
+// been synced is served from mod.io's first page instead of an empty list. ?sort=hot orders a bounded
+// candidate pool by hotRanking, and the other ?sort= values by a sorted index (see
+// ModRepository.ListOrders), instead of newest mod first. ?hasMedia=, ?tags= and ?tagMatch= filter the
+// list, see parseListFilter. Lists are paged with ?page= and ?limit= so only the page's mods are loaded,
+// see ModRepository.GetListPage; cached lists are otherwise streamed, see streamModList, and carry
+// validators, see listETag.
+func modListHandler(modRepo *repository.ModRepository, live *liveFallthrough, present presenter, hot hotRanking, strictParams bool, itemTypeTag string, itemType string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
 			return
 		}
 
-		scripts, lastUpdated, err := modRepo.GetModsByType(r.Context(), modio.ScriptModTag)
+		if !checkQueryParams(w, r, strictParams, listQueryParams) {
+			return
+		}
+
+		filter, ok := parseListFilter(w, r)
+		if !ok {
+			return
+		}
+
+		sortBy := r.URL.Query().Get("sort")
+		order := sortBy
+		if sortBy == sortHot {
+			order = repository.ListOrderDownloads // Ranked after loading, see hotRanking.candidateIDs
+		} else if orders := modRepo.ListOrders(); sortBy != "" && !slices.Contains(orders, sortBy) {
+			http.Error(w, fmt.Sprintf("Invalid 'sort' query parameter: must be one of %s, %s", strings.Join(orders, ", "), sortHot), http.StatusBadRequest)
+			return
+		}
+
+		page, limit, ok := parseListPage(w, r)
+		if !ok {
+			return
+		}
+
+		var ids, pageIDs []string // Hot lists rank from every id, the others only read their page
+		var total int
+		var err error
+		if sortBy == sortHot {
+			ids, err = modRepo.GetListModIDs(r.Context(), itemTypeTag, order, filter)
+			total = len(ids)
+		} else {
+			pageIDs, total, err = modRepo.GetListPage(r.Context(), itemTypeTag, order, filter, (page-1)*limit, limit)
+		}
 		if err != nil {
-			slog.Error("Failed to get scripts from repository", "error", err)
+			slog.Error("Failed to get mods from repository", "type", itemType, "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 			return
 		}
+		lastUpdated, err := modRepo.GetLastWriteTimestampForType(r.Context(), itemTypeTag)
+		if err != nil {
+			slog.Warn("Could not get last write timestamp of type", "type", itemType, "error", err)
+		}
+		// Validators follow every write, including event and webhook updates a type's full sync time misses.
+		lastWrite, err := modRepo.GetLastOverallWriteTimestamp(r.Context())
+		if err != nil {
+			slog.Warn("Could not get last overall write timestamp", "type", itemType, "error", err)
+		}
+		if lastUpdated.IsZero() {
+			lastUpdated = lastWrite // No full sync of the type has succeeded since per-type timestamps exist
+		}
+
+		generation, err := modRepo.GetSyncGeneration(r.Context())
+		if err != nil {
+			slog.Warn("Failed to get sync generation", "type", itemType, "error", err)
+		} else {
+			w.Header().Set("X-Sync-Generation", strconv.FormatInt(generation, 10))
+		}
 
 		response := APIResponse{
-			ItemType:    "scripts",
-			LastUpdated: lastUpdated,
-			Count:       len(scripts),
-			Items:       scripts,
+			ItemType:    itemType,
+			LastUpdated: optionalTime(lastUpdated),
+			SyncStatus:  syncStatusFor(lastUpdated),
+			Source:      SourceCache,
+			Page:        page,
+			PerPage:     limit,
 		}
-		writeJSONResponse(w, http.StatusOK, response)
+		if live != nil && total == 0 && lastUpdated.IsZero() {
+			liveMods, err := live.fetch(r.Context(), itemTypeTag)
+			if err != nil {
+				slog.Warn("Live fallthrough to mod.io failed, serving empty cache", "type", itemType, "error", err)
+			} else {
+				liveMods = append([]modio.Mod(nil), liveMods...) // The fallthrough's copy is shared
+				liveMods = filterLiveMods(liveMods, filter, modRepo.NormalizeForIndex)
+				if sortBy == sortHot {
+					hot.sort(liveMods, time.Now())
+				} else {
+					sortLiveMods(liveMods, order)
+				}
+				start, end := pageBounds(len(liveMods), page, limit)
+				response.Source, response.TotalCount = SourceLive, len(liveMods)
+				response.Count, response.Items = end-start, present.mods(liveMods[start:end])
+				setDataSource(w, DataSourceLive)
+				writeJSONResponse(w, http.StatusOK, response)
+				return
+			}
+		}
+		setDataSource(w, DataSourceRedis)
+		if !lastWrite.IsZero() && checkNotModified(w, r, listETag(generation, lastWrite, total), lastWrite) {
+			return
+		}
+
+		response.TotalCount = total
+		if sortBy == sortHot {
+			byUpdated, err := modRepo.GetListModIDs(r.Context(), itemTypeTag, repository.ListOrderUpdatedDesc, filter)
+			if err != nil {
+				slog.Error("Failed to get mods from repository", "type", itemType, "error", err)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			// Ranking needs every candidate at once, so this response is buffered.
+			candidates := hot.candidateIDs(ids, byUpdated)
+			mods := make([]modio.Mod, 0, len(candidates))
+			dropped, err := modRepo.StreamMods(r.Context(), candidates, func(mod *modio.Mod) error {
+				mods = append(mods, *mod)
+				return nil
+			})
+			if err != nil {
+				slog.Error("Failed to get mods from repository", "type", itemType, "error", err)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			hot.sort(mods, time.Now())
+			start, end := pageBounds(len(mods), page, limit)
+			response.TotalCount = len(candidates)
+			response.Count, response.Dropped, response.Items = end-start, dropped, present.mods(mods[start:end])
+			if dropped > 0 {
+				w.Header().Set("X-Partial-Results", strconv.Itoa(dropped))
+			}
+			writeJSONResponse(w, http.StatusOK, response)
+			return
+		}
+
+		streamModList(w, response, func(emit func(modio.Mod) error) (int, error) {
+			return modRepo.StreamMods(r.Context(), pageIDs, func(mod *modio.Mod) error {
+				return emit(present.listMod(*mod))
+			})
+		})
+	}
+}
+
+// sortLiveMods orders mods read from mod.io the way GetListModIDs orders cached ones.
+func sortLiveMods(mods []modio.Mod, order string) {
+	var less func(a, b *modio.Mod) bool
+	switch order {
+	case repository.ListOrderUpdated:
+		less = func(a, b *modio.Mod) bool { return a.DateUpdated < b.DateUpdated }
+	case repository.ListOrderUpdatedDesc:
+		less = func(a, b *modio.Mod) bool { return a.DateUpdated > b.DateUpdated }
+	case repository.ListOrderAdded:
+		less = func(a, b *modio.Mod) bool { return a.DateAdded < b.DateAdded }
+	case repository.ListOrderAddedDesc:
+		less = func(a, b *modio.Mod) bool { return a.DateAdded > b.DateAdded }
+	case repository.ListOrderDownloads:
+		less = func(a, b *modio.Mod) bool { return a.Stats.DownloadsTotal > b.Stats.DownloadsTotal }
+	case repository.ListOrderComments:
+		less = func(a, b *modio.Mod) bool { return a.Stats.CommentsTotal > b.Stats.CommentsTotal }
+	case repository.ListOrderName:
+		less = func(a, b *modio.Mod) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	default:
+		less = func(a, b *modio.Mod) bool { return a.ID > b.ID }
+	}
+	sort.SliceStable(mods, func(i, j int) bool { return less(&mods[i], &mods[j]) })
+}
+
+// filterLiveMods applies filter to mods read from mod.io the way GetListModIDs applies it to cached ones,
+// comparing tags in their indexed (normalized) form.
+func filterLiveMods(mods []modio.Mod, filter repository.ListFilter, normalize func(string) string) []modio.Mod {
+	wanted := make(map[string]bool, len(filter.Tags))
+	for _, tag := range filter.Tags {
+		wanted[normalize(tag)] = true
+	}
+	filtered := make([]modio.Mod, 0, len(mods))
+	for _, mod := range mods {
+		if filter.HasMedia != nil && (len(mod.Media.Images) > 0) != *filter.HasMedia {
+			continue
+		}
+		if len(wanted) > 0 {
+			matched := make(map[string]bool, len(wanted))
+			for _, tag := range mod.Tags {
+				if normalized := normalize(tag.Name); wanted[normalized] {
+					matched[normalized] = true
+				}
+			}
+			if len(matched) == 0 || (!filter.AnyTag && len(matched) < len(wanted)) {
+				continue
+			}
+		}
+		filtered = append(filtered, mod)
+	}
+	return filtered
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// syncStatusFor lets clients tell "genuinely empty" apart from "no sync has written data yet".
+func syncStatusFor(lastUpdated time.Time) string {
+	if lastUpdated.IsZero() {
+		return SyncStatusPending
 	}
+	return SyncStatusReady
 }
 
-func AutocompleteHandler(modRepo *repository.ModRepository, itemTypeTag string) http.HandlerFunc {
+// maxAutocompleteOffset bounds autocomplete paging; ZRANGEBYLEX walks every skipped member.
+const maxAutocompleteOffset = 1000
+
+// AutocompleteHandler suggests titles by prefix, paged with ?offset=. An explicit limit above the caller's
+// cap (the admin cap when the request carries the admin token) is rejected with 400 rather than clamped.
+func AutocompleteHandler(cfg *config.AppConfig, modRepo *repository.ModRepository, itemTypeTag string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
@@ -97,16 +416,48 @@ func AutocompleteHandler(modRepo *repository.ModRepository, itemTypeTag string)
 			http.Error(w, "Missing or empty 'prefix' query parameter", http.StatusBadRequest)
 			return
 		}
+		if utf8.RuneCountInString(prefix) < cfg.AutocompleteMinPrefixLength {
+			// Very short prefixes match huge ranges and are rarely useful; answer without querying Redis.
+			w.Header().Set("X-Autocomplete-Hint", fmt.Sprintf("prefix must be at least %d characters", cfg.AutocompleteMinPrefixLength))
+			writeJSONResponse(w, http.StatusOK, []AutocompleteSuggestion{})
+			return
+		}
+
+		limit := cfg.AutocompleteDefaultLimit
+		if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+			maxLimit := cfg.AutocompleteMaxLimit
+			if hasAdminToken(r, cfg.AdminToken) {
+				maxLimit = cfg.AutocompleteAdminMaxLimit
+			}
+			l, err := strconv.Atoi(limitStr)
+			if err != nil || l < 1 || l > maxLimit {
+				http.Error(w, fmt.Sprintf("Invalid 'limit' query parameter: must be an integer between 1 and %d", maxLimit), http.StatusBadRequest)
+				return
+			}
+			limit = l
+		}
+
+		offset := 0
+		if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+			o, err := strconv.Atoi(offsetStr)
+			if err != nil || o < 0 || o > maxAutocompleteOffset {
+				http.Error(w, fmt.Sprintf("Invalid 'offset' query parameter: must be an integer between 0 and %d", maxAutocompleteOffset), http.StatusBadRequest)
+				return
+			}
+			offset = o
+		}
 
-		limitStr := r.URL.Query().Get("limit")
-		limit := 10 // Default limit
-		if limitStr != "" {
-			if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 50 { // Max limit 50
-				limit = l
+		highlight := false
+		if highlightStr := r.URL.Query().Get("highlight"); highlightStr != "" {
+			parsed, err := strconv.ParseBool(highlightStr)
+			if err != nil {
+				http.Error(w, "Invalid 'highlight' query parameter: expected true or false", http.StatusBadRequest)
+				return
 			}
+			highlight = parsed
 		}
 
-		results, err := modRepo.SearchTitlesByPrefix(r.Context(), itemTypeTag, prefix, limit)
+		results, err := modRepo.SearchTitlesByPrefix(r.Context(), itemTypeTag, prefix, offset, limit)
 		if err != nil {
 			slog.Error("Failed to get autocomplete suggestions", "prefix", prefix, "type", itemTypeTag, "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -115,23 +466,645 @@ func AutocompleteHandler(modRepo *repository.ModRepository, itemTypeTag string)
 
 		suggestions := make([]AutocompleteSuggestion, 0, len(results))
 		for _, res := range results {
-			parts := strings.SplitN(res, ":", 2) // Split "normalizedtitle:id"
-			if len(parts) == 2 {
-				id, err := strconv.Atoi(parts[1])
-				if err == nil {
-					// For the title, ideally, you'd fetch the original title from mod:<id>
-					// as the indexed title is normalized (lowercase).
-					// For simplicity here, we're using the normalized part.
-					// A more complete solution would MGET the actual mod objects or store original title in ZSET member.
-					suggestions = append(suggestions, AutocompleteSuggestion{ID: id, Title: parts[0]})
-				}
+			sep := strings.LastIndex(res, ":") // Split "normalizedtitle:id"; titles may contain colons
+			if sep < 0 {
+				continue
+			}
+			id, err := strconv.Atoi(res[sep+1:])
+			if err == nil {
+				suggestions = append(suggestions, AutocompleteSuggestion{ID: id, Title: res[:sep]})
 			}
 		}
+		suggestions = withOriginalTitles(r.Context(), modRepo, suggestions, prefix, highlight)
+		setDataSource(w, DataSourceRedis)
 		writeJSONResponse(w, http.StatusOK, suggestions)
 	}
 }
 
-func HealthCheckHandler(modRepo *repository.ModRepository) http.HandlerFunc {
+// withOriginalTitles swaps the normalized titles the index stores for the mods' original ones and, with
+// highlight, locates the prefix match in each. Suggestions whose mod can't be loaded keep the normalized
+// title without a match.
+func withOriginalTitles(ctx context.Context, modRepo *repository.ModRepository, suggestions []AutocompleteSuggestion, prefix string, highlight bool) []AutocompleteSuggestion {
+	if len(suggestions) == 0 {
+		return suggestions
+	}
+	ids := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		ids[i] = strconv.Itoa(suggestion.ID)
+	}
+	mods, err := modRepo.GetModsByIDs(ctx, ids)
+	if err != nil {
+		slog.Warn("Failed to load mods for autocomplete titles, serving normalized titles", "error", err)
+		return suggestions
+	}
+	titles := make(map[int]string, len(mods))
+	for _, mod := range mods {
+		titles[mod.ID] = mod.Name
+	}
+
+	normalizedPrefix := modRepo.NormalizeForIndex(prefix)
+	for i := range suggestions {
+		title, ok := titles[suggestions[i].ID]
+		if !ok {
+			continue
+		}
+		suggestions[i].Title = title
+		if !highlight {
+			continue
+		}
+		if span, ok := matchSpan(modRepo.NormalizeForIndex, title, normalizedPrefix); ok {
+			suggestions[i].Match = &span
+		}
+	}
+	return suggestions
+}
+
+const maxSlugBatchSize = 100
+
+// ModHandler serves a single cached mod by id, answering 304 to conditional requests for an unchanged mod.
+func ModHandler(modRepo *repository.ModRepository, present presenter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		modID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || modID <= 0 {
+			http.Error(w, "Invalid mod id", http.StatusBadRequest)
+			return
+		}
+
+		mod, err := modRepo.GetModByID(r.Context(), modID)
+		if err != nil {
+			slog.Error("Failed to get mod from repository", "mod_id", modID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if mod == nil {
+			http.Error(w, "Mod not found", http.StatusNotFound)
+			return
+		}
+		setDataSource(w, DataSourceRedis)
+		var lastModified time.Time
+		if mod.DateUpdated > 0 {
+			lastModified = time.Unix(mod.DateUpdated, 0)
+		}
+		if checkNotModified(w, r, modETag(mod), lastModified) {
+			return
+		}
+		if err := modRepo.RestoreFullDescription(r.Context(), mod); err != nil {
+			slog.Warn("Serving truncated description", "mod_id", modID, "error", err)
+		}
+		writeJSONResponse(w, http.StatusOK, present.mod(*mod))
+	}
+}
+
+type ModRankResponse struct {
+	ID     int    `json:"id"`
+	Type   string `json:"type"`
+	Metric string `json:"metric"`
+	repository.ModRank
+}
+
+// ModRankHandler reports a cached mod's position among its type by ?metric= (downloads by default,
+// updated, or comments when comment counts are indexed).
+func ModRankHandler(modRepo *repository.ModRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		modID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || modID <= 0 {
+			http.Error(w, "Invalid mod id", http.StatusBadRequest)
+			return
+		}
+		metric := r.URL.Query().Get("metric")
+		if metric == "" {
+			metric = repository.RankMetricDownloads
+		}
+
+		mod, err := modRepo.GetModByID(r.Context(), modID)
+		if err != nil {
+			slog.Error("Failed to get mod from repository", "mod_id", modID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		typeTag := ""
+		if mod != nil {
+			typeTag = modRepo.ModTypeTag(mod)
+		}
+		if typeTag == "" {
+			http.Error(w, "Mod not found", http.StatusNotFound)
+			return
+		}
+
+		rank, err := modRepo.GetModRank(r.Context(), typeTag, metric, modID)
+		if errors.Is(err, repository.ErrUnknownRankMetric) {
+			http.Error(w, "Invalid 'metric' query parameter: expected downloads, updated or comments (when comment counts are indexed)", http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to get mod rank", "mod_id", modID, "metric", metric, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if rank == nil {
+			http.Error(w, "Mod not found in the index", http.StatusNotFound)
+			return
+		}
+		setDataSource(w, DataSourceRedis)
+		writeJSONResponse(w, http.StatusOK, ModRankResponse{ID: modID, Type: repository.GetModTypeFromTag(typeTag), Metric: metric, ModRank: *rank})
+	}
+}
+
+type SlugLookupResponse struct {
+	Count      int          `json:"count"`
+	Items      []*modio.Mod `json:"items"`
+	Unresolved []string     `json:"unresolved"`
+}
+
+// ModsBySlugsHandler resolves a comma-separated list of name_id slugs (?ids=slug-a,slug-b) to cached mods
+// in one request, reporting any slug that isn't indexed.
+func ModsBySlugsHandler(modRepo *repository.ModRepository, present presenter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slugs := splitCommaList(r.URL.Query().Get("ids"))
+		if len(slugs) == 0 {
+			http.Error(w, "Missing or empty 'ids' query parameter", http.StatusBadRequest)
+			return
+		}
+		if len(slugs) > maxSlugBatchSize {
+			http.Error(w, fmt.Sprintf("Too many slugs requested: %d (max %d)", len(slugs), maxSlugBatchSize), http.StatusBadRequest)
+			return
+		}
+
+		resolved, err := modRepo.ResolveSlugs(r.Context(), slugs)
+		if err != nil {
+			slog.Error("Failed to resolve slugs", "count", len(slugs), "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+
+		ids := make([]string, 0, len(resolved))
+		unresolved := make([]string, 0)
+		for _, slug := range slugs {
+			if id, ok := resolved[slug]; ok {
+				ids = append(ids, id)
+			} else {
+				unresolved = append(unresolved, slug)
+			}
+		}
+
+		mods, err := modRepo.GetModsByIDs(r.Context(), ids)
+		if err != nil {
+			slog.Error("Failed to get mods for resolved slugs", "count", len(ids), "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if len(mods) < len(ids) {
+			// The slug index pointed at a blob that no longer exists; report those slugs as unresolved too.
+			found := make(map[string]bool, len(mods))
+			for _, mod := range mods {
+				found[strconv.Itoa(mod.ID)] = true
+			}
+			for _, slug := range slugs {
+				if id, ok := resolved[slug]; ok && !found[id] {
+					unresolved = append(unresolved, slug)
+				}
+			}
+		}
+
+		setDataSource(w, DataSourceRedis)
+		writeJSONResponse(w, http.StatusOK, SlugLookupResponse{Count: len(mods), Items: present.modPtrs(mods), Unresolved: unresolved})
+	}
+}
+
+// splitCommaList splits a comma-separated query value, trimming entries and dropping empty and duplicate ones.
+func splitCommaList(raw string) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, value := range strings.Split(raw, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		values = append(values, value)
+	}
+	return values
+}
+
+type ModCountResponse struct {
+	ItemType string   `json:"itemType"`
+	Tags     []string `json:"tags"`
+	Count    int64    `json:"count"`
+}
+
+// ModCountHandler counts a type's cached mods carrying every ?tag= (repeatable or comma-separated)
+// without loading them. With no tags it counts the whole type.
+func ModCountHandler(modRepo *repository.ModRepository, itemTypeTag string, itemType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tags := []string{}
+		for _, raw := range r.URL.Query()["tag"] {
+			tags = append(tags, splitCommaList(raw)...)
+		}
+
+		count, err := modRepo.CountModsByFilter(r.Context(), itemTypeTag, tags)
+		if err != nil {
+			slog.Error("Failed to count mods by filter", "type", itemType, "tags", tags, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		setDataSource(w, DataSourceRedis)
+		writeJSONResponse(w, http.StatusOK, ModCountResponse{ItemType: itemType, Tags: tags, Count: count})
+	}
+}
+
+type AuthorModsResponse struct {
+	AuthorID     int          `json:"authorId"`
+	UpdatedSince int64        `json:"updatedSince,omitempty"`
+	Count        int          `json:"count"`
+	Items        []*modio.Mod `json:"items"`
+}
+
+// ModsByAuthorHandler lists a submitter's mods across types, optionally only those updated after
+// ?updatedSince=<unix seconds>.
+func ModsByAuthorHandler(modRepo *repository.ModRepository, present presenter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := strconv.Atoi(chi.URLParam(r, "userID"))
+		if err != nil || userID <= 0 {
+			http.Error(w, "Invalid user ID", http.StatusBadRequest)
+			return
+		}
+
+		var updatedSince int64
+		if sinceStr := r.URL.Query().Get("updatedSince"); sinceStr != "" {
+			parsed, err := strconv.ParseInt(sinceStr, 10, 64)
+			if err != nil || parsed < 0 {
+				http.Error(w, "Invalid 'updatedSince' query parameter: expected a non-negative unix timestamp", http.StatusBadRequest)
+				return
+			}
+			updatedSince = parsed
+		}
+
+		mods, err := modRepo.GetModsByAuthorUpdatedSince(r.Context(), userID, updatedSince)
+		if err != nil {
+			slog.Error("Failed to get mods by author", "user_id", userID, "updated_since", updatedSince, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		setDataSource(w, DataSourceRedis)
+		writeJSONResponse(w, http.StatusOK, AuthorModsResponse{AuthorID: userID, UpdatedSince: updatedSince, Count: len(mods), Items: present.modPtrs(mods)})
+	}
+}
+
+type ModfilesResponse struct {
+	ModID int                  `json:"modId"`
+	Count int                  `json:"count"`
+	Items []modio.ModioModfile `json:"items"`
+}
+
+// ModfilesHandler lists a cached mod's modfile history, newest first, read through from mod.io so users
+// can roll back to an older version. Mods outside the cache are 404 rather than proxied.
+func ModfilesHandler(modRepo *repository.ModRepository, modioClient *modio.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		modID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || modID <= 0 {
+			http.Error(w, "Invalid mod id", http.StatusBadRequest)
+			return
+		}
+
+		cachedMod, err := modRepo.GetModByID(r.Context(), modID)
+		if err != nil {
+			slog.Error("Failed to get cached mod for modfiles", "mod_id", modID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if cachedMod == nil {
+			http.Error(w, "Mod not found", http.StatusNotFound)
+			return
+		}
+
+		modfiles, err := modioClient.GetModfiles(r.Context(), modID)
+		if errors.Is(err, modio.ErrNotFound) {
+			http.Error(w, "Mod not found on mod.io", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to fetch modfiles from mod.io", "mod_id", modID, "error", err)
+			http.Error(w, "Bad Gateway", http.StatusBadGateway)
+			return
+		}
+		if modfiles == nil {
+			modfiles = []modio.ModioModfile{}
+		}
+		setDataSource(w, DataSourceLive)
+		writeJSONResponse(w, http.StatusOK, ModfilesResponse{ModID: modID, Count: len(modfiles), Items: modfiles})
+	}
+}
+
+type DependenciesResponse struct {
+	ModID         int          `json:"modId"`
+	DependencyIDs []int        `json:"dependencyIds"`
+	Items         []*modio.Mod `json:"items"`      // Dependencies found in the cache
+	Unresolved    []int        `json:"unresolved"` // Dependencies that aren't cached, e.g. other mod types
+}
+
+// DependenciesHandler lists the mods a cached mod depends on, read through from mod.io and resolved to
+// cached mods where possible. Mods whose dependencies flag is unset skip the mod.io call.
+func DependenciesHandler(modRepo *repository.ModRepository, modioClient *modio.Client, present presenter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		modID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || modID <= 0 {
+			http.Error(w, "Invalid mod id", http.StatusBadRequest)
+			return
+		}
+
+		cachedMod, err := modRepo.GetModByID(r.Context(), modID)
+		if err != nil {
+			slog.Error("Failed to get cached mod for dependencies", "mod_id", modID, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		if cachedMod == nil {
+			http.Error(w, "Mod not found", http.StatusNotFound)
+			return
+		}
+
+		response := DependenciesResponse{ModID: modID, DependencyIDs: []int{}, Items: []*modio.Mod{}, Unresolved: []int{}}
+		if !cachedMod.Dependencies {
+			setDataSource(w, DataSourceRedis)
+			writeJSONResponse(w, http.StatusOK, response)
+			return
+		}
+
+		dependencies, err := modioClient.GetModDependencies(r.Context(), modID)
+		if errors.Is(err, modio.ErrNotFound) {
+			http.Error(w, "Mod not found on mod.io", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to fetch dependencies from mod.io", "mod_id", modID, "error", err)
+			http.Error(w, "Bad Gateway", http.StatusBadGateway)
+			return
+		}
+
+		ids := make([]string, len(dependencies))
+		for i, dependency := range dependencies {
+			response.DependencyIDs = append(response.DependencyIDs, dependency.ModID)
+			ids[i] = strconv.Itoa(dependency.ModID)
+		}
+		mods, err := modRepo.GetModsByIDs(r.Context(), ids)
+		if err != nil {
+			slog.Error("Failed to get cached dependencies", "mod_id", modID, "count", len(ids), "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		found := make(map[int]bool, len(mods))
+		for _, mod := range mods {
+			found[mod.ID] = true
+		}
+		for _, id := range response.DependencyIDs {
+			if !found[id] {
+				response.Unresolved = append(response.Unresolved, id)
+			}
+		}
+		response.Items = present.modPtrs(mods)
+		setDataSource(w, DataSourceLive)
+		writeJSONResponse(w, http.StatusOK, response)
+	}
+}
+
+type TagOptionsResponse struct {
+	Count int                        `json:"count"`
+	Items []modio.ModioGameTagOption `json:"items"`
+}
+
+// TagOptionsHandler serves the game's tag schema from mod.io, cached in Redis for ttl.
+func TagOptionsHandler(modRepo *repository.ModRepository, modioClient *modio.Client, ttl time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		options, err := modRepo.GetCachedTagOptions(r.Context())
+		if err != nil {
+			slog.Warn("Failed to read cached tag options, fetching from mod.io", "error", err)
+		}
+		source := DataSourceRedis
+		if options == nil {
+			source = DataSourceLive
+			options, err = modioClient.GetGameTagOptions(r.Context())
+			if err != nil {
+				slog.Error("Failed to fetch tag options from mod.io", "error", err)
+				http.Error(w, "Bad Gateway", http.StatusBadGateway)
+				return
+			}
+			if options == nil {
+				options = []modio.ModioGameTagOption{}
+			}
+			if err := modRepo.SetCachedTagOptions(r.Context(), options, ttl); err != nil {
+				slog.Warn("Failed to cache tag options", "error", err)
+			}
+		}
+		setDataSource(w, source)
+		writeJSONResponse(w, http.StatusOK, TagOptionsResponse{Count: len(options), Items: options})
+	}
+}
+
+type TypeSummary struct {
+	ItemType     string     `json:"itemType"`
+	Count        int64      `json:"count"`
+	OldestUpdate *time.Time `json:"oldestUpdate,omitempty"` // Omitted while the type has no mods
+	NewestUpdate *time.Time `json:"newestUpdate,omitempty"`
+	LastSynced   *time.Time `json:"lastSynced,omitempty"` // When a full sync of the type last succeeded
+}
+
+// GameSummary is the game as mod.io reported it at startup; modsCountTotal covers every mod, typed or not.
+type GameSummary struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	ModsCountTotal int    `json:"modsCountTotal"`
+}
+
+type SummaryResponse struct {
+	LastUpdated *time.Time    `json:"lastUpdated,omitempty"`
+	SyncStatus  string        `json:"syncStatus"`
+	Game        *GameSummary  `json:"game,omitempty"` // Omitted if the startup game lookup was skipped or failed
+	Types       []TypeSummary `json:"types"`
+}
+
+// SummaryHandler reports each type's mod count and the span of their update dates, read from the ends of
+// the date indexes rather than by loading mods.
+func SummaryHandler(modRepo *repository.ModRepository, modioClient *modio.Client, categories []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lastUpdated, err := modRepo.GetLastOverallWriteTimestamp(r.Context())
+		if err != nil {
+			slog.Warn("Could not get last overall write timestamp for summary", "error", err)
+		}
+		response := SummaryResponse{LastUpdated: optionalTime(lastUpdated), SyncStatus: syncStatusFor(lastUpdated), Types: []TypeSummary{}}
+		if game := modioClient.CachedGameInfo(); game != nil {
+			response.Game = &GameSummary{ID: game.ID, Name: game.Name, ModsCountTotal: game.Stats.ModsCountTotal}
+		}
+		for _, category := range categories {
+			t := struct{ tag, itemType string }{category, itemTypeName(category)}
+			count, err := modRepo.CountModsByFilter(r.Context(), t.tag, nil)
+			if err != nil {
+				slog.Error("Failed to count mods for summary", "type", t.itemType, "error", err)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			oldest, newest, err := modRepo.GetDateRange(r.Context(), t.tag)
+			if err != nil {
+				slog.Error("Failed to get date range for summary", "type", t.itemType, "error", err)
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			lastSynced, err := modRepo.GetLastWriteTimestampForType(r.Context(), t.tag)
+			if err != nil {
+				slog.Warn("Could not get last write timestamp of type for summary", "type", t.itemType, "error", err)
+			}
+			summary := TypeSummary{ItemType: t.itemType, Count: count, LastSynced: optionalTime(lastSynced)}
+			if newest > 0 {
+				summary.OldestUpdate = optionalTime(time.Unix(oldest, 0).UTC())
+				summary.NewestUpdate = optionalTime(time.Unix(newest, 0).UTC())
+			}
+			response.Types = append(response.Types, summary)
+		}
+		setDataSource(w, DataSourceRedis)
+		writeJSONResponse(w, http.StatusOK, response)
+	}
+}
+
+type DeletedModsResponse struct {
+	Since int64                   `json:"since"`
+	Count int                     `json:"count"`
+	Items []repository.DeletedMod `json:"items"`
+}
+
+// DeletedModsHandler lists mods deleted after ?since=<unix seconds> so delta-syncing clients know what to
+// prune. Omitting since returns the whole retained log.
+func DeletedModsHandler(modRepo *repository.ModRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var since int64
+		if sinceStr := r.URL.Query().Get("since"); sinceStr != "" {
+			parsed, err := strconv.ParseInt(sinceStr, 10, 64)
+			if err != nil || parsed < 0 {
+				http.Error(w, "Invalid 'since' query parameter: expected a non-negative unix timestamp", http.StatusBadRequest)
+				return
+			}
+			since = parsed
+		}
+
+		deleted, err := modRepo.GetRecentlyDeletedMods(r.Context(), since)
+		if err != nil {
+			slog.Error("Failed to get recently deleted mods", "since", since, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		setDataSource(w, DataSourceRedis)
+		writeJSONResponse(w, http.StatusOK, DeletedModsResponse{Since: since, Count: len(deleted), Items: deleted})
+	}
+}
+
+// ChangesHandler lists mods changed and deleted after ?sinceGeneration=N, so clients holding the lists
+// of generation N can catch up without refetching them. 410 means the history is gone and lists must
+// be refetched.
+func ChangesHandler(modRepo *repository.ModRepository) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		since, err := strconv.ParseInt(r.URL.Query().Get("sinceGeneration"), 10, 64)
+		if err != nil || since < 0 {
+			http.Error(w, "Invalid 'sinceGeneration' query parameter: expected a non-negative generation", http.StatusBadRequest)
+			return
+		}
+
+		changes, err := modRepo.GetChangesSince(r.Context(), since)
+		if errors.Is(err, repository.ErrChangesUnavailable) {
+			http.Error(w, "Changes since this generation are no longer available, refetch the lists", http.StatusGone)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to get changes since generation", "since_generation", since, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("X-Sync-Generation", strconv.FormatInt(changes.Generation, 10))
+		setDataSource(w, DataSourceRedis)
+		writeJSONResponse(w, http.StatusOK, changes)
+	}
+}
+
+// LivenessHandler reports that the process is up. It checks no dependencies, so a Redis or mod.io outage
+// doesn't get the instance restarted; /health/ready covers those.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, http.StatusOK, map[string]string{"status": "alive"})
+	}
+}
+
+// ReadinessHandler reports whether this instance should receive traffic: Redis answers and a full sync
+// has succeeded, in this process or, for at least one type, by any instance sharing the cache. Event
+// cycles and failed full syncs don't count, since they leave an empty cache empty. With
+// BLOCK_READY_UNTIL_SYNCED it stays 503 until this instance's own first full sync has completed.
+func ReadinessHandler(cfg *config.AppConfig, modRepo *repository.ModRepository, dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if cfg.BlockReadyUntilSynced && !dataScheduler.HasCompletedFullSync() {
+			status := map[string]string{"status": "not_ready", "reason": "initial_sync_pending"}
+			if initial := dataScheduler.InitialSyncStatus(); initial != nil && initial.Error != "" {
+				status["reason"] = "initial_sync_failed"
+				status["initial_sync_state"] = initial.State
+				status["initial_sync_error"] = initial.Error
+			}
+			writeJSONResponse(w, http.StatusServiceUnavailable, status)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+		if err := modRepo.ReadClient().Ping(ctx).Err(); err != nil {
+			slog.Error("Readiness check failed: Redis ping error", "error", err)
+			status := map[string]string{"status": "not_ready", "reason": "redis_connection_error"}
+			writeJSONResponse(w, http.StatusServiceUnavailable, status)
+			return
+		}
+		if !dataScheduler.HasCompletedFullSync() {
+			synced, err := anyTypeFullySynced(ctx, modRepo, cfg.ModCategories)
+			if err != nil {
+				slog.Error("Readiness check failed: could not get last write timestamps of types", "error", err)
+				status := map[string]string{"status": "not_ready", "reason": "redis_read_error"}
+				writeJSONResponse(w, http.StatusServiceUnavailable, status)
+				return
+			}
+			if !synced {
+				status := map[string]string{"status": "not_ready", "reason": "cache_not_synced"}
+				writeJSONResponse(w, http.StatusServiceUnavailable, status)
+				return
+			}
+		}
+		writeJSONResponse(w, http.StatusOK, map[string]string{"status": "ready"})
+	}
+}
+
+// anyTypeFullySynced reports whether a full sync of at least one of the types has ever succeeded.
+func anyTypeFullySynced(ctx context.Context, modRepo *repository.ModRepository, typeTags []string) (bool, error) {
+	for _, tag := range typeTags {
+		lastSynced, err := modRepo.GetLastWriteTimestampForType(ctx, tag)
+		if err != nil {
+			return false, err
+		}
+		if !lastSynced.IsZero() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// requireSynced answers 503 on data routes until the first full sync has completed.
+func requireSynced(dataScheduler *scheduler.Scheduler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !dataScheduler.HasCompletedFullSync() {
+				w.Header().Set("Retry-After", "30")
+				status := map[string]string{"status": "not_ready", "reason": "initial_sync_pending"}
+				writeJSONResponse(w, http.StatusServiceUnavailable, status)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// HealthCheckHandler reports Redis connectivity. While mod.io rejects the API key the status is
+// "degraded": cached data is still served but no longer refreshed.
+func HealthCheckHandler(modRepo *repository.ModRepository, dataScheduler *scheduler.Scheduler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
@@ -149,8 +1122,33 @@ func HealthCheckHandler(modRepo *repository.ModRepository) http.HandlerFunc {
 			writeJSONResponse(w, http.StatusServiceUnavailable, status)
 			return
 		}
+		if readClient := modRepo.ReadClient(); readClient != redisClient {
+			if err := readClient.Ping(ctx).Err(); err != nil {
+				slog.Error("Health check failed: Redis read replica ping error", "error", err)
+				status := map[string]string{"status": "unhealthy", "reason": "redis_read_replica_connection_error"}
+				writeJSONResponse(w, http.StatusServiceUnavailable, status)
+				return
+			}
+		}
 
 		status := map[string]string{"status": "ok", "redis": "connected"}
+		if failure := dataScheduler.AuthFailure(); failure != nil {
+			status["status"] = "degraded"
+			status["reason"] = "modio_auth_failure"
+			status["modio_auth_failed_at"] = failure.DetectedAt.Format(time.RFC3339)
+			status["modio_auth_retry_at"] = failure.RetryAt.Format(time.RFC3339)
+		}
+		if initial := dataScheduler.InitialSyncStatus(); initial != nil {
+			status["initial_sync"] = initial.State
+			failed := initial.State == scheduler.InitialSyncRetrying || initial.State == scheduler.InitialSyncFailed
+			if failed && !dataScheduler.HasCompletedFullSync() {
+				if status["status"] == "ok" {
+					status["status"] = "degraded"
+					status["reason"] = "initial_sync_failed"
+				}
+				status["initial_sync_error"] = initial.Error
+			}
+		}
 		writeJSONResponse(w, http.StatusOK, status)
 	}
 }
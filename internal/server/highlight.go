@@ -0,0 +1,43 @@
+package server
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MatchSpan locates the matched prefix within an original title, in characters (code points).
+type MatchSpan struct {
+	Start  int `json:"start"`
+	Length int `json:"length"`
+}
+
+// matchSpan maps a normalized prefix match back onto the original title: the match is the shortest
+// leading part of the title that normalizes to something starting with normalizedPrefix, extended over
+// trailing combining marks, so casing and accent folding still highlight the right characters.
+func matchSpan(normalize func(string) string, title, normalizedPrefix string) (MatchSpan, bool) {
+	trimmed := strings.TrimLeftFunc(title, unicode.IsSpace)
+	start := utf8.RuneCountInString(title) - utf8.RuneCountInString(trimmed)
+
+	end := -1
+	for i := range trimmed {
+		if i > 0 && strings.HasPrefix(normalize(trimmed[:i]), normalizedPrefix) {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		if !strings.HasPrefix(normalize(trimmed), normalizedPrefix) {
+			return MatchSpan{}, false
+		}
+		end = len(trimmed)
+	}
+	for end < len(trimmed) {
+		r, size := utf8.DecodeRuneInString(trimmed[end:])
+		if !unicode.Is(unicode.Mn, r) {
+			break
+		}
+		end += size
+	}
+	return MatchSpan{Start: start, Length: utf8.RuneCountInString(trimmed[:end])}, true
+}
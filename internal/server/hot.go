@@ -0,0 +1,58 @@
+package server
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/config"
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+)
+
+const sortHot = "hot"
+
+// hotRanking scores mods for ?sort=hot: popularity on a log2 scale minus one point per half-life since
+// the last update, so a mod has to double its popularity every half-life to keep its place.
+type hotRanking struct {
+	halfLife         time.Duration
+	subscriberWeight int // Downloads each subscriber counts as
+	candidates       int // Mods taken from the top of each of the downloads and date_updated orders
+}
+
+func newHotRanking(cfg *config.AppConfig) hotRanking {
+	return hotRanking{halfLife: cfg.HotSortHalfLife, subscriberWeight: cfg.HotSortSubscriberWeight, candidates: cfg.HotSortCandidates}
+}
+
+// candidateIDs merges the heads of the downloads and date_updated orders into the pool that gets ranked.
+// Mods outside both heads are less downloaded than the popular candidates and older than the recent ones,
+// so they are left out of the hot list, which keeps the blobs it loads bounded however many mods are cached.
+func (h hotRanking) candidateIDs(byDownloads, byUpdated []string) []string {
+	ids := make([]string, 0, min(len(byDownloads), h.candidates)+min(len(byUpdated), h.candidates))
+	seen := make(map[string]bool, cap(ids))
+	for _, head := range [][]string{byDownloads[:min(len(byDownloads), h.candidates)], byUpdated[:min(len(byUpdated), h.candidates)]} {
+		for _, id := range head {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func (h hotRanking) score(mod modio.Mod, now time.Time) float64 {
+	popularity := float64(mod.Stats.DownloadsTotal) + float64(h.subscriberWeight)*float64(mod.Stats.SubscribersTotal)
+	age := max(now.Sub(time.Unix(mod.DateUpdated, 0)).Hours(), 0)
+	return math.Log2(1+popularity) - age/h.halfLife.Hours()
+}
+
+// sort orders mods hottest first in place; ties keep their cached order.
+func (h hotRanking) sort(mods []modio.Mod, now time.Time) {
+	scores := make(map[int]float64, len(mods))
+	for _, mod := range mods {
+		scores[mod.ID] = h.score(mod, now)
+	}
+	sort.SliceStable(mods, func(i, j int) bool {
+		return scores[mods[i].ID] > scores[mods[j].ID]
+	})
+}
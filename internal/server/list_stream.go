@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+)
+
+const listStreamFlushEvery = 100 // Items written between flushes to the client
+
+// listEnvelope is APIResponse without the fields streamModList writes itself.
+type listEnvelope struct {
+	ItemType    string     `json:"itemType"`
+	LastUpdated *time.Time `json:"lastUpdated,omitempty"`
+	SyncStatus  string     `json:"syncStatus"`
+	Source      string     `json:"source"`
+	Page        int        `json:"page"`
+	PerPage     int        `json:"perPage"`
+	TotalCount  int        `json:"totalCount"`
+}
+
+// streamModList writes envelope as one JSON object whose items come from each, which calls emit once per
+// mod, so a response never holds the whole list. count and dropped follow the items since they are only
+// known at the end, and X-Partial-Results is sent as a trailer. A failure before the first item is still
+// a clean 500; after it the status line is gone, so the response is aborted and the body left truncated.
+func streamModList(w http.ResponseWriter, envelope APIResponse, each func(emit func(modio.Mod) error) (int, error)) {
+	head, err := json.Marshal(listEnvelope{
+		ItemType:    envelope.ItemType,
+		LastUpdated: envelope.LastUpdated,
+		SyncStatus:  envelope.SyncStatus,
+		Source:      envelope.Source,
+		Page:        envelope.Page,
+		PerPage:     envelope.PerPage,
+		TotalCount:  envelope.TotalCount,
+	})
+	if err != nil {
+		jsonEncodeFailures.Add(1)
+		slog.Error("Failed to encode JSON response", "status", http.StatusOK, "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	body := bufio.NewWriterSize(w, 32<<10)
+	count := 0
+	started := false
+	start := func() {
+		started = true
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Trailer", "X-Partial-Results")
+		w.WriteHeader(http.StatusOK)
+		body.Write(head[:len(head)-1]) // Reopen the object for the streamed fields
+		body.WriteString(`,"items":[`)
+	}
+
+	dropped, err := each(func(mod modio.Mod) error {
+		item, err := json.Marshal(mod)
+		if err != nil {
+			jsonEncodeFailures.Add(1)
+			return fmt.Errorf("failed to encode mod %d: %w", mod.ID, err)
+		}
+		if !started {
+			start()
+		}
+		if count > 0 {
+			body.WriteByte(',')
+		}
+		if _, err := body.Write(item); err != nil {
+			return err
+		}
+		count++
+		if count%listStreamFlushEvery == 0 {
+			if err := body.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if !started {
+			slog.Error("Failed to stream mod list", "type", envelope.ItemType, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		slog.Error("Aborting streamed mod list", "type", envelope.ItemType, "items_written", count, "error", err)
+		panic(http.ErrAbortHandler)
+	}
+
+	if !started {
+		start()
+	}
+	fmt.Fprintf(body, `],"count":%d`, count)
+	if dropped > 0 {
+		fmt.Fprintf(body, `,"dropped":%d`, dropped)
+	}
+	body.WriteString("}\n")
+	if err := body.Flush(); err != nil {
+		slog.Debug("Failed to write JSON response", "error", err)
+	}
+	if dropped > 0 {
+		w.Header().Set("X-Partial-Results", strconv.Itoa(dropped))
+	}
+}
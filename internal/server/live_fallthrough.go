@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+)
+
+const (
+	SourceCache = "cache"
+	SourceLive  = "live"
+
+	liveFallthroughPageSize = 100
+	liveFallthroughTTL      = time.Minute // Bounds mod.io calls while a cold cache is being populated
+	liveFallthroughTimeout  = 10 * time.Second
+)
+
+type liveEntry struct {
+	mods      []modio.Mod
+	fetchedAt time.Time
+}
+
+// liveFallthrough serves a first page straight from mod.io for types the cache has no data for yet.
+// Results are kept briefly per type so concurrent cold-start requests share one upstream call.
+type liveFallthrough struct {
+	client *modio.Client
+
+	mu      sync.Mutex // Held across the fetch so only one request per instance calls mod.io at a time
+	entries map[string]liveEntry
+}
+
+// newLiveFallthrough returns nil when the client is nil, which disables the fallback.
+func newLiveFallthrough(client *modio.Client) *liveFallthrough {
+	if client == nil {
+		return nil
+	}
+	return &liveFallthrough{client: client, entries: make(map[string]liveEntry)}
+}
+
+func (f *liveFallthrough) fetch(ctx context.Context, itemTypeTag string) ([]modio.Mod, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if entry, ok := f.entries[itemTypeTag]; ok && time.Since(entry.fetchedAt) < liveFallthroughTTL {
+		return entry.mods, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, liveFallthroughTimeout)
+	defer cancel()
+	mods, err := f.client.FetchLatestItems(ctx, itemTypeTag, liveFallthroughPageSize)
+	if err != nil {
+		return nil, err
+	}
+	f.entries[itemTypeTag] = liveEntry{mods: mods, fetchedAt: time.Now()}
+	return mods, nil
+}
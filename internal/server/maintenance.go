@@ -0,0 +1,70 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// maintenanceMode is a per-instance switch that makes the data routes answer 503 while Redis is being
+// worked on. Health and admin routes stay up.
+type maintenanceMode struct {
+	enabled    atomic.Bool
+	retryAfter time.Duration
+}
+
+func newMaintenanceMode(enabled bool, retryAfter time.Duration) *maintenanceMode {
+	m := &maintenanceMode{retryAfter: retryAfter}
+	m.enabled.Store(enabled)
+	return m
+}
+
+type MaintenanceResponse struct {
+	Status            string `json:"status"`
+	Message           string `json:"message,omitempty"`
+	RetryAfterSeconds int    `json:"retryAfterSeconds"`
+}
+
+func (m *maintenanceMode) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.enabled.Load() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		seconds := int(m.retryAfter / time.Second)
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		writeJSONResponse(w, http.StatusServiceUnavailable, MaintenanceResponse{
+			Status:            "maintenance",
+			Message:           "The API is undergoing maintenance, please retry later",
+			RetryAfterSeconds: seconds,
+		})
+	})
+}
+
+type MaintenanceRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+type MaintenanceStateResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+// MaintenanceHandler reports maintenance mode on GET and switches it with a {"enabled": bool} body on POST.
+// The switch only affects this instance and resets to MAINTENANCE_MODE on restart.
+func MaintenanceHandler(m *maintenanceMode) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var req MaintenanceRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&req); err != nil || req.Enabled == nil {
+				http.Error(w, "Invalid JSON body: expected {\"enabled\": true|false}", http.StatusBadRequest)
+				return
+			}
+			m.enabled.Store(*req.Enabled)
+			slog.Warn("Maintenance mode switched", "enabled", *req.Enabled)
+		}
+		writeJSONResponse(w, http.StatusOK, MaintenanceStateResponse{Enabled: m.enabled.Load()})
+	}
+}
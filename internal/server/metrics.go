@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/metrics"
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/ShawnEdgell/modio-api-go/internal/scheduler"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// instrumentHTTP counts requests and their latency by chi route pattern rather than raw path, so mod ids
+// and other path parameters don't each create a series. Unmatched requests are labelled "unmatched".
+func instrumentHTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		defer func() {
+			route := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK // Nothing was written
+			}
+			metrics.HTTPRequests.WithLabelValues(route, r.Method, strconv.Itoa(status)).Inc()
+			metrics.HTTPRequestDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+		}()
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// registerStateMetrics exports counters and results the client, scheduler and handlers already keep.
+func registerStateMetrics(modioClient *modio.Client, dataScheduler *scheduler.Scheduler) {
+	metrics.RegisterCounterFunc("modio_retries_total", "mod.io requests retried after a transient failure.",
+		func() float64 { return float64(modioClient.Retries()) })
+	metrics.RegisterCounterFunc("skipped_noop_updates_total", "Edit events skipped because the mod was unchanged.",
+		func() float64 { return float64(dataScheduler.SkippedNoopUpdates()) })
+	metrics.RegisterCounterFunc("json_encode_failures_total", "Responses that failed to encode and were answered with a 500.",
+		func() float64 { return float64(JSONEncodeFailures()) })
+	lastAudit := func(count func(*scheduler.IntegrityAuditResult) int) func() float64 {
+		return func() float64 {
+			if audit := dataScheduler.LastIntegrityAudit(); audit != nil {
+				return float64(count(audit))
+			}
+			return 0
+		}
+	}
+	metrics.RegisterGaugeFunc("audit_index_entries_without_blob", "Type index entries without a mod blob in the last integrity audit.",
+		lastAudit(func(a *scheduler.IntegrityAuditResult) int { return a.IndexEntriesWithoutBlob }))
+	metrics.RegisterGaugeFunc("audit_blobs_missing_from_index", "Mod blobs missing from their type index in the last integrity audit.",
+		lastAudit(func(a *scheduler.IntegrityAuditResult) int { return a.BlobsMissingFromIndex }))
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// trustedRealIP is a replacement for chi's middleware.RealIP that only honors forwarding headers
+// when the direct peer is one of the configured trusted proxies. Otherwise the socket address is kept,
+// so clients can't spoof their IP by sending the headers themselves.
+func trustedRealIP(trustedProxies []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(trustedProxies) > 0 {
+				if peerIP := remoteIP(r.RemoteAddr); peerIP != nil && isTrustedProxy(peerIP, trustedProxies) {
+					if clientIP := forwardedClientIP(r, trustedProxies); clientIP != "" {
+						r.RemoteAddr = clientIP
+					}
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedClientIP walks X-Forwarded-For from right to left, skipping trusted hops, and returns the
+// first untrusted address. X-Real-IP is used when X-Forwarded-For is absent.
+func forwardedClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := net.ParseIP(strings.TrimSpace(hops[i]))
+			if hop == nil {
+				return ""
+			}
+			if !isTrustedProxy(hop, trustedProxies) || i == 0 {
+				return hop.String()
+			}
+		}
+	}
+	if xrip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); xrip != nil {
+		return xrip.String()
+	}
+	return ""
+}
+
+func remoteIP(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+func isTrustedProxy(ip net.IP, trustedProxies []*net.IPNet) bool {
+	for _, network := range trustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// requireAdminToken rejects requests that don't carry the configured admin bearer token.
+// When no token is configured the admin endpoints are disabled entirely.
+func requireAdminToken(adminToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if adminToken == "" {
+				http.Error(w, "Admin endpoints are disabled", http.StatusForbidden)
+				return
+			}
+			if !hasAdminToken(r, adminToken) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func hasAdminToken(r *http.Request, adminToken string) bool {
+	if adminToken == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(adminToken)) == 1
+}
+
+// limitConcurrentPerIP caps in-flight requests per client IP, answering 429 beyond the cap, so one client
+// can't tie up the Redis connection pool. It must run after trustedRealIP. A cap of zero disables it.
+func limitConcurrentPerIP(maxInFlight int) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	inFlight := make(map[string]int)
+	return func(next http.Handler) http.Handler {
+		if maxInFlight <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.RemoteAddr
+			if ip := remoteIP(r.RemoteAddr); ip != nil {
+				key = ip.String()
+			}
+
+			mu.Lock()
+			if inFlight[key] >= maxInFlight {
+				mu.Unlock()
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "Too many concurrent requests", http.StatusTooManyRequests)
+				return
+			}
+			inFlight[key]++
+			mu.Unlock()
+
+			defer func() {
+				mu.Lock()
+				if inFlight[key]--; inFlight[key] == 0 {
+					delete(inFlight, key)
+				}
+				mu.Unlock()
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+const clientIDHeader = "X-Client-ID"
+
+// requireClientID rejects requests without an X-Client-ID header when required is set, and with a
+// non-empty allowlist also those whose id isn't in it. It passes everything through otherwise.
+func requireClientID(required bool, allowlist map[string]bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !required {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientID := strings.TrimSpace(r.Header.Get(clientIDHeader))
+			if clientID == "" {
+				http.Error(w, "Missing X-Client-ID header", http.StatusBadRequest)
+				return
+			}
+			if len(allowlist) > 0 && !allowlist[clientID] {
+				http.Error(w, "Unknown X-Client-ID", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+const (
+	corsAllowedMethods = "GET, OPTIONS"
+	corsMaxAge         = "600" // Seconds browsers may cache a preflight answer
+)
+
+var (
+	corsAllowedHeaders = strings.Join([]string{"Content-Type", "If-None-Match", clientIDHeader}, ", ")
+	corsExposedHeaders = strings.Join([]string{"ETag", "X-Data-Source", "X-Sync-Generation", "X-Partial-Results", "X-Autocomplete-Hint"}, ", ")
+)
+
+// cors adds CORS headers for requests from allowed origins and answers their preflight OPTIONS requests
+// itself, before routing. An empty allowlist allows any origin with "*".
+func cors(allowedOrigins map[string]bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			header := w.Header()
+			if len(allowedOrigins) == 0 {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Add("Vary", "Origin")
+				if !allowedOrigins[origin] {
+					next.ServeHTTP(w, r) // Without the header the browser withholds the response
+					return
+				}
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				header.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+				header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+				header.Set("Access-Control-Max-Age", corsMaxAge)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
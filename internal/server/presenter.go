@@ -0,0 +1,89 @@
+package server
+
+import (
+	"github.com/ShawnEdgell/modio-api-go/internal/config"
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+)
+
+// presenter shapes cached mods for responses. With normalized tags enabled each tag also carries the
+// form the tag index uses, so clients can match tags against tag-filter results. List responses leave
+// out the large image sizes and the modfile changelog, which only the detail response carries. The
+// submitter is cut down per SUBMITTER_LIST_FIELDS and SUBMITTER_DETAIL_FIELDS.
+type presenter struct {
+	normalizeTag    func(string) string // nil leaves tags as mod.io returned them
+	listSubmitter   string
+	detailSubmitter string
+}
+
+func newPresenter(cfg *config.AppConfig, modRepo *repository.ModRepository) presenter {
+	p := presenter{listSubmitter: cfg.SubmitterListFields, detailSubmitter: cfg.SubmitterDetailFields}
+	if cfg.IncludeNormalizedTags {
+		p.normalizeTag = modRepo.NormalizeForIndex
+	}
+	return p
+}
+
+// mod returns a copy of mod ready to serve as a detail response. The input is never modified, so
+// shared values are safe.
+func (p presenter) mod(mod modio.Mod) modio.Mod {
+	mod.SubmittedBy = projectSubmitter(mod.SubmittedBy, p.detailSubmitter)
+	return p.withTags(mod)
+}
+
+func (p presenter) withTags(mod modio.Mod) modio.Mod {
+	if p.normalizeTag == nil || len(mod.Tags) == 0 {
+		return mod
+	}
+	tags := make([]modio.ModioTag, len(mod.Tags))
+	for i, tag := range mod.Tags {
+		tags[i] = modio.ModioTag{Name: tag.Name, Normalized: p.normalizeTag(tag.Name)}
+	}
+	mod.Tags = tags
+	return mod
+}
+
+// listMod is mod without the large logo and media sizes or the changelog.
+func (p presenter) listMod(mod modio.Mod) modio.Mod {
+	mod.SubmittedBy = projectSubmitter(mod.SubmittedBy, p.listSubmitter)
+	mod = p.withTags(mod)
+	mod.Modfile.Changelog = ""
+	mod.Logo.Thumb640x360 = ""
+	mod.Logo.Thumb1280x720 = ""
+	if len(mod.Media.Images) > 0 {
+		images := make([]modio.ModioImage, len(mod.Media.Images))
+		for i, image := range mod.Media.Images {
+			image.Thumb1280x720 = ""
+			images[i] = image
+		}
+		mod.Media.Images = images
+	}
+	return mod
+}
+
+func projectSubmitter(user modio.ModioUser, fields string) modio.ModioUser {
+	switch fields {
+	case config.SubmitterFieldsUsername:
+		return modio.ModioUser{Username: user.Username}
+	case config.SubmitterFieldsNone:
+		return modio.ModioUser{}
+	}
+	return user
+}
+
+func (p presenter) mods(mods []modio.Mod) []modio.Mod {
+	presented := make([]modio.Mod, len(mods))
+	for i := range mods {
+		presented[i] = p.listMod(mods[i])
+	}
+	return presented
+}
+
+func (p presenter) modPtrs(mods []*modio.Mod) []*modio.Mod {
+	presented := make([]*modio.Mod, len(mods))
+	for i, mod := range mods {
+		presentedMod := p.listMod(*mod)
+		presented[i] = &presentedMod
+	}
+	return presented
+}
@@ -5,38 +5,119 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ShawnEdgell/modio-api-go/internal/config"
+	"github.com/ShawnEdgell/modio-api-go/internal/metrics"
 	"github.com/ShawnEdgell/modio-api-go/internal/modio"
 	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+	"github.com/ShawnEdgell/modio-api-go/internal/scheduler"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	slogchi "github.com/samber/slog-chi"
 )
 
-func NewRouter(modRepo *repository.ModRepository) *chi.Mux {
+func NewRouter(cfg *config.AppConfig, modRepo *repository.ModRepository, modioClient *modio.Client, dataScheduler *scheduler.Scheduler) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(trustedRealIP(cfg.TrustedProxies)) // Only honors X-Forwarded-For/X-Real-IP from TRUSTED_PROXIES
 	// Replace chi's default logger with slog-chi
 	// It will use the slog.Default() logger configured in your main.go
 	r.Use(slogchi.New(slog.Default()))
-	r.Use(middleware.Recoverer) // Recoverer should generally be after the logger
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(instrumentHTTP)
+	r.Use(cors(cfg.CORSAllowedOrigins)) // Answers preflight requests before routing
+	r.Use(middleware.Recoverer)         // Recoverer should generally be after the logger
+	if cfg.CompressResponses {
+		r.Use(compressResponses(cfg.CompressMinBytes))
+	}
+	registerStateMetrics(modioClient, dataScheduler)
 
-	r.Get("/api/v1/skaterxl/maps", MapsHandler(modRepo))
-	r.Get("/api/v1/skaterxl/scripts", ScriptsHandler(modRepo))
+	var live *liveFallthrough
+	if cfg.LiveFallthrough {
+		live = newLiveFallthrough(modioClient)
+	}
 
-	r.Get("/api/v1/skaterxl/maps/autocomplete", AutocompleteHandler(modRepo, modio.MapTag))
-	r.Get("/api/v1/skaterxl/scripts/autocomplete", AutocompleteHandler(modRepo, modio.ScriptModTag))
+	apiBase := "/api/v1/" + cfg.ModioGameSlug
+	present := newPresenter(cfg, modRepo)
+	hot := newHotRanking(cfg)
+	maintenance := newMaintenanceMode(cfg.MaintenanceMode, cfg.MaintenanceRetryAfter)
 
-	r.Get("/health", HealthCheckHandler(modRepo))
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(60 * time.Second))
 
-	r.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/" {
-			http.NotFound(w, r)
-			return
+		// Data routes; maintenance mode short-circuits them while health and admin stay available.
+		r.Group(func(r chi.Router) {
+			r.Use(maintenance.middleware)
+			r.Use(limitConcurrentPerIP(cfg.MaxConcurrentRequestsPerIP))
+			if cfg.BlockDataUntilSynced {
+				r.Use(requireSynced(dataScheduler))
+			}
+
+			// Public API routes; mod.io's webhook calls carry no client id.
+			r.Group(func(r chi.Router) {
+				r.Use(requireClientID(cfg.RequireClientID, cfg.ClientIDs))
+
+				for _, category := range cfg.ModCategories {
+					typeBase := apiBase + "/" + itemTypeName(category)
+					r.Get(typeBase, ModListHandler(modRepo, live, present, hot, cfg.StrictQueryParams, category))
+					r.Get(typeBase+"/autocomplete", AutocompleteHandler(cfg, modRepo, category))
+					r.Get(typeBase+"/count", ModCountHandler(modRepo, category, itemTypeName(category)))
+					r.Get(typeBase+"/search", SearchHandler(modRepo, present, category))
+				}
+
+				r.Get(apiBase+"/mods/slugs", ModsBySlugsHandler(modRepo, present))
+				r.Get(apiBase+"/mods/{id}", ModHandler(modRepo, present))
+				r.Get(apiBase+"/mods/{id}/files", ModfilesHandler(modRepo, modioClient))
+				r.Get(apiBase+"/mods/{id}/dependencies", DependenciesHandler(modRepo, modioClient, present))
+				r.Get(apiBase+"/mods/{id}/rank", ModRankHandler(modRepo))
+				r.Get(apiBase+"/mods/by-author/{userID}", ModsByAuthorHandler(modRepo, present))
+				r.Get(apiBase+"/summary", SummaryHandler(modRepo, modioClient, modRepo.IndexedTypeTags()))
+				r.Get(apiBase+"/deleted", DeletedModsHandler(modRepo))
+				r.Get(apiBase+"/changes", ChangesHandler(modRepo))
+				r.Get(apiBase+"/tag-options", TagOptionsHandler(modRepo, modioClient, cfg.TagOptionsCacheTTL))
+			})
+
+			r.Post("/webhook/modio", ModioWebhookHandler(cfg.ModioWebhookSecret, dataScheduler))
+		})
+
+		r.Get("/health", HealthCheckHandler(modRepo, dataScheduler))
+		r.Get("/health/live", LivenessHandler())
+		r.Get("/health/ready", ReadinessHandler(cfg, modRepo, dataScheduler))
+		if cfg.MetricsEnabled {
+			r.Handle("/metrics", metrics.Handler())
 		}
-		http.Redirect(w, r, "https://www.skatebit.app", http.StatusMovedPermanently)
+
+		r.Group(func(r chi.Router) {
+			r.Use(requireAdminToken(cfg.AdminToken))
+			r.Get("/admin/config", ConfigHandler(cfg))
+			r.Get("/admin/export.csv", ExportCSVHandler(modRepo, dataScheduler))
+			r.Get("/admin/maintenance", MaintenanceHandler(maintenance))
+			r.Post("/admin/maintenance", MaintenanceHandler(maintenance))
+			r.Get("/admin/mods/{id}/diff", ModDiffHandler(modRepo, modioClient))
+			r.Post("/admin/refresh", RefreshModsHandler(dataScheduler))
+			r.Post("/admin/full-sync", FullSyncHandler(dataScheduler))
+			r.Get("/admin/full-sync", FullSyncQueueHandler(dataScheduler))
+			r.Get("/admin/initial-sync", InitialSyncHandler(dataScheduler))
+			r.Post("/admin/event-sync", EventSyncHandler(dataScheduler))
+			r.Post("/admin/event-cursor/reset", ResetEventCursorHandler(dataScheduler))
+			r.Get("/admin/integrity-audit", IntegrityAuditHandler(cfg, dataScheduler))
+			r.Put("/admin/modio-key", ModioKeyHandler(modioClient, dataScheduler))
+		})
+
+		r.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/" {
+				http.NotFound(w, r)
+				return
+			}
+			http.Redirect(w, r, "https://www.skatebit.app", http.StatusMovedPermanently)
+		})
+	})
+
+	// Long-lived streams are kept out of the request timeout group.
+	r.With(maintenance.middleware).Get(apiBase+"/sync/events", SyncEventsHandler(newSyncEventHub(modRepo, cfg.MaxSSESubscribers)))
+
+	r.Group(func(r chi.Router) {
+		r.Use(requireAdminToken(cfg.AdminToken))
+		r.Get("/admin/full-sync/stream", FullSyncStreamHandler(dataScheduler))
 	})
 
 	return r
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+)
+
+// maxSearchTokens bounds how many word sets one search intersects.
+const maxSearchTokens = 10
+
+// searchOrders are the ?sort= values of the search endpoints; the first is the default.
+var searchOrders = []string{repository.SearchOrderDownloads, repository.SearchOrderUpdated}
+
+// SearchHandler serves the mods of a type whose name or summary contains every word of ?q=, matched as
+// whole normalized words, ranked by ?sort=downloads (default) or ?sort=updated and paged with ?page= and
+// ?limit= like the list endpoints. The word index fills in as mods are next synced.
+func SearchHandler(modRepo *repository.ModRepository, present presenter, itemTypeTag string) http.HandlerFunc {
+	itemType := itemTypeName(itemTypeTag)
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := strings.TrimSpace(r.URL.Query().Get("q"))
+		if query == "" {
+			http.Error(w, "Missing or empty 'q' query parameter", http.StatusBadRequest)
+			return
+		}
+		tokens := modRepo.SearchTokens(query)
+		if len(tokens) > maxSearchTokens {
+			http.Error(w, fmt.Sprintf("Invalid 'q' query parameter: at most %d words", maxSearchTokens), http.StatusBadRequest)
+			return
+		}
+
+		order := r.URL.Query().Get("sort")
+		if order == "" {
+			order = searchOrders[0]
+		}
+		page, limit, ok := parseListPage(w, r)
+		if !ok {
+			return
+		}
+
+		ids, total, err := modRepo.SearchModIDs(r.Context(), itemTypeTag, tokens, order, limit, (page-1)*limit)
+		if errors.Is(err, repository.ErrUnknownSearchOrder) {
+			http.Error(w, "Invalid 'sort' query parameter: must be one of "+strings.Join(searchOrders, ", "), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			slog.Error("Failed to search mods", "type", itemType, "query", query, "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		lastUpdated, err := modRepo.GetLastWriteTimestampForType(r.Context(), itemTypeTag)
+		if err != nil {
+			slog.Warn("Could not get last write timestamp of type", "type", itemType, "error", err)
+		}
+		if lastUpdated.IsZero() {
+			if lastUpdated, err = modRepo.GetLastOverallWriteTimestamp(r.Context()); err != nil {
+				slog.Warn("Could not get last overall write timestamp", "type", itemType, "error", err)
+			}
+		}
+
+		response := APIResponse{
+			ItemType:    itemType,
+			LastUpdated: optionalTime(lastUpdated),
+			SyncStatus:  syncStatusFor(lastUpdated),
+			Source:      SourceCache,
+			Page:        page,
+			PerPage:     limit,
+			TotalCount:  int(total),
+		}
+		setDataSource(w, DataSourceRedis)
+		streamModList(w, response, func(emit func(modio.Mod) error) (int, error) {
+			return modRepo.StreamMods(r.Context(), ids, func(mod *modio.Mod) error {
+				return emit(present.listMod(*mod))
+			})
+		})
+	}
+}
@@ -11,11 +11,13 @@ import (
 	"time"
 
 	"github.com/ShawnEdgell/modio-api-go/internal/config"
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
 	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+	"github.com/ShawnEdgell/modio-api-go/internal/scheduler"
 )
 
-func Run(cfg *config.AppConfig, modRepo *repository.ModRepository) error { 
-	router := NewRouter(modRepo) 
+func Run(cfg *config.AppConfig, modRepo *repository.ModRepository, modioClient *modio.Client, dataScheduler *scheduler.Scheduler) error {
+	router := NewRouter(cfg, modRepo, modioClient, dataScheduler)
 
 	srv := &http.Server{
 		Addr:         ":" + cfg.ServerPort,
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/repository"
+)
+
+const (
+	syncEventBufferSize    = 4 // Per-subscriber backlog before events are dropped for that client
+	syncEventKeepaliveTime = 30 * time.Second
+)
+
+// syncEventHub fans a single Redis subscription out to every local SSE client. The subscription is
+// opened with the first client and closed when the last one leaves.
+type syncEventHub struct {
+	modRepo        *repository.ModRepository
+	maxSubscribers int
+
+	mu          sync.Mutex
+	subscribers map[chan string]struct{}
+	stop        context.CancelFunc // Stops the shared subscription; nil while there are no subscribers
+}
+
+func newSyncEventHub(modRepo *repository.ModRepository, maxSubscribers int) *syncEventHub {
+	return &syncEventHub{
+		modRepo:        modRepo,
+		maxSubscribers: maxSubscribers,
+		subscribers:    make(map[chan string]struct{}),
+	}
+}
+
+// subscribe registers a client and returns its event channel, or false when the cap is reached.
+func (h *syncEventHub) subscribe() (chan string, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.subscribers) >= h.maxSubscribers {
+		return nil, false
+	}
+	ch := make(chan string, syncEventBufferSize)
+	h.subscribers[ch] = struct{}{}
+	if h.stop == nil {
+		ctx, stop := context.WithCancel(context.Background())
+		h.stop = stop
+		go h.run(ctx)
+	}
+	return ch, true
+}
+
+func (h *syncEventHub) unsubscribe(ch chan string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers, ch)
+	if len(h.subscribers) == 0 && h.stop != nil {
+		h.stop()
+		h.stop = nil
+	}
+}
+
+func (h *syncEventHub) run(ctx context.Context) {
+	pubsub := h.modRepo.SubscribeSyncCompleted(ctx)
+	defer pubsub.Close()
+	slog.Info("Opened shared sync event subscription")
+	defer slog.Info("Closed shared sync event subscription")
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			h.broadcast(msg.Payload)
+		}
+	}
+}
+
+// broadcast never blocks on a slow client: a full buffer means that client misses the event.
+func (h *syncEventHub) broadcast(payload string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- payload:
+		default:
+			slog.Debug("Dropping sync event for slow SSE subscriber")
+		}
+	}
+}
+
+// SyncEventsHandler streams a "sync" server-sent event each time a sync completes. Connections beyond
+// the configured cap get 503.
+func SyncEventsHandler(hub *syncEventHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		events, ok := hub.subscribe()
+		if !ok {
+			w.Header().Set("Retry-After", "30")
+			http.Error(w, "Too many event stream subscribers", http.StatusServiceUnavailable)
+			return
+		}
+		defer hub.unsubscribe(events)
+
+		// Subscribers stay connected indefinitely, so lift the server's write deadline for this response.
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+			slog.Warn("Could not clear write deadline for SSE stream", "error", err)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		keepalive := time.NewTicker(syncEventKeepaliveTime)
+		defer keepalive.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-keepalive.C:
+				if _, err := w.Write([]byte(": keepalive\n\n")); err != nil {
+					return
+				}
+				flusher.Flush()
+			case payload := <-events:
+				if err := writeSSEEvent(w, "sync", json.RawMessage(payload)); err != nil {
+					slog.Debug("Sync event stream client went away", "error", err)
+					return
+				}
+				flusher.Flush()
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ShawnEdgell/modio-api-go/internal/modio"
+	"github.com/ShawnEdgell/modio-api-go/internal/scheduler"
+)
+
+const (
+	webhookSignatureHeader = "X-Modio-Signature"
+	maxWebhookBodyBytes    = 1 << 20
+)
+
+// ModioWebhookHandler accepts pushed mod.io events, either a single event object or {"data": [...]},
+// signed with an HMAC-SHA256 of the raw body. Valid events are queued for the scheduler and 202 is returned.
+// When the queue is full an event sync is requested instead, so polling picks the events up soon.
+func ModioWebhookHandler(secret string, dataScheduler *scheduler.Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if secret == "" {
+			http.Error(w, "Webhook ingestion is disabled", http.StatusForbidden)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes))
+		if err != nil {
+			http.Error(w, "Request body too large or unreadable", http.StatusBadRequest)
+			return
+		}
+		if !validWebhookSignature(secret, body, r.Header.Get(webhookSignatureHeader)) {
+			slog.Warn("Rejected mod.io webhook with invalid signature", "remote_addr", r.RemoteAddr)
+			http.Error(w, "Invalid signature", http.StatusUnauthorized)
+			return
+		}
+
+		events, err := parseWebhookEvents(body)
+		if err != nil {
+			http.Error(w, "Invalid webhook payload", http.StatusBadRequest)
+			return
+		}
+		if err := dataScheduler.EnqueueWebhookEvents(events); err != nil {
+			if errors.Is(err, scheduler.ErrWebhookQueueFull) {
+				slog.Warn("Webhook queue full, leaving events to event polling", "count", len(events))
+				dataScheduler.RequestEventSync()
+				w.Header().Set("Retry-After", "60")
+				http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			slog.Error("Failed to enqueue webhook events", "count", len(events), "error", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		writeJSONResponse(w, http.StatusAccepted, map[string]int{"accepted": len(events)})
+	}
+}
+
+// validWebhookSignature checks a hex HMAC-SHA256 signature, optionally prefixed with "sha256=".
+func validWebhookSignature(secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+func parseWebhookEvents(body []byte) ([]modio.ModioEvent, error) {
+	var envelope struct {
+		Data []modio.ModioEvent `json:"data"`
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			return nil, err
+		}
+	}
+	if len(envelope.Data) > 0 {
+		return validWebhookEvents(envelope.Data)
+	}
+	var event modio.ModioEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, err
+	}
+	return validWebhookEvents([]modio.ModioEvent{event})
+}
+
+func validWebhookEvents(events []modio.ModioEvent) ([]modio.ModioEvent, error) {
+	for _, event := range events {
+		if event.ModID <= 0 || event.EventType == "" {
+			return nil, errors.New("webhook event is missing mod_id or event_type")
+		}
+	}
+	return events, nil
+}
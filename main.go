@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"os"
@@ -18,12 +19,12 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-var rdb *redis.Client
+var rdb, readRdb *redis.Client
 
-func initRedis(cfg *config.AppConfig) (*redis.Client, error) {
-	slog.Info("Initializing Redis client", "address", cfg.RedisAddr, "db", cfg.RedisDB)
+func initRedis(cfg *config.AppConfig, addr string) (*redis.Client, error) {
+	slog.Info("Initializing Redis client", "address", addr, "db", cfg.RedisDB)
 	rdbInstance := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisAddr,
+		Addr:     addr,
 		Password: cfg.RedisPassword,
 		DB:       cfg.RedisDB,
 	})
@@ -32,13 +33,33 @@ func initRedis(cfg *config.AppConfig) (*redis.Client, error) {
 	defer cancel()
 
 	if _, err := rdbInstance.Ping(ctx).Result(); err != nil {
-		slog.Error("Failed to connect to Redis", "error", err)
+		slog.Error("Failed to connect to Redis", "address", addr, "error", err)
 		return nil, err
 	}
-	slog.Info("Successfully connected to Redis")
+	slog.Info("Successfully connected to Redis", "address", addr)
 	return rdbInstance, nil
 }
 
+// verifyGame logs the configured game and its mod count, exiting if mod.io doesn't know the game id so a
+// wrong MODIO_GAME_ID fails at once instead of as an empty sync. Other failures only warn, since the cache
+// can still be served while mod.io is unreachable.
+func verifyGame(client *modio.Client, gameID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	game, err := client.GetGameInfo(ctx)
+	if errors.Is(err, modio.ErrNotFound) {
+		slog.Error("Mod.io has no game with the configured id, check MODIO_GAME_ID", "game_id", gameID, "error", err)
+		os.Exit(1)
+	}
+	if err != nil {
+		slog.Warn("Could not fetch game info from Mod.io, continuing without it", "game_id", gameID, "error", err)
+		return
+	}
+	slog.Info("Serving mods for Mod.io game", "game_id", game.ID, "name", game.Name, "mods_total", game.Stats.ModsCountTotal,
+		"downloads_total", game.Stats.ModsDownloadsTotal, "subscribers_total", game.Stats.ModsSubscribersTotal)
+}
+
 func main() {
 	loggerHandler := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo, // Use LevelInfo for production, LevelDebug for development
@@ -59,24 +80,38 @@ func main() {
 	}
 
 	appConfig := config.Load()
+	slog.Info("Configuration loaded", "values", appConfig.Values)
+	if len(appConfig.UnrecognizedEnv) > 0 {
+		slog.Warn("Unrecognized environment variables look like settings; check for typos", "names", appConfig.UnrecognizedEnv)
+	}
 
 	modioClient, err := modio.NewClient(appConfig)
 	if err != nil {
 		slog.Error("Failed to create Mod.io client", "error", err)
 		os.Exit(1)
 	}
+	if appConfig.VerifyGameOnStartup {
+		verifyGame(modioClient, appConfig.ModioGameID)
+	}
 
-	rdb, err = initRedis(appConfig)
+	rdb, err = initRedis(appConfig, appConfig.RedisAddr)
 	if err != nil {
 		slog.Error("Failed to initialize Redis", "error", err)
 		os.Exit(1)
 	}
+	if appConfig.RedisReadAddr != "" {
+		readRdb, err = initRedis(appConfig, appConfig.RedisReadAddr)
+		if err != nil {
+			slog.Error("Failed to initialize Redis read replica", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	slog.Info("Initializing Mod Repository")
-	modRepo := repository.NewModRepository(rdb)
+	modRepo := repository.NewModRepository(rdb, readRdb, appConfig)
 
 	slog.Info("Initializing data scheduler")
-	dataScheduler := scheduler.NewScheduler(modioClient, modRepo, appConfig)
+	dataScheduler := scheduler.NewScheduler(modioClient, modRepo.Primary(), appConfig)
 	dataScheduler.Start()
 
 	stopOsSignal := make(chan os.Signal, 1)
@@ -85,7 +120,7 @@ func main() {
 	serverErrChan := make(chan error, 1)
 	go func() {
 		slog.Info("Starting HTTP server", "port", appConfig.ServerPort)
-		if err := server.Run(appConfig, modRepo); err != nil && err != http.ErrServerClosed {
+		if err := server.Run(appConfig, modRepo, modioClient, dataScheduler); err != nil && err != http.ErrServerClosed {
 			slog.Error("HTTP server error", "error", err)
 			serverErrChan <- err
 		} else if err == http.ErrServerClosed {
@@ -131,6 +166,11 @@ func main() {
 			slog.Info("Redis connection closed")
 		}
 	}
+	if readRdb != nil {
+		if err := readRdb.Close(); err != nil {
+			slog.Error("Failed to close Redis read replica connection", "error", err)
+		}
+	}
 
 	if serverErr != nil {
 		slog.Error("Application exited due to server error", "error", serverErr)